@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+)
+
+// Reconstrói a ordem das cartas de uma mão a partir da seed salva no commitment
+// da mão. Sem -hand é usada a mão atual do jogo
+//
+//	go run ./cmd/replay -game <game_id> -hand <n>
+//	go run ./cmd/replay -seed <seed>
+func main() {
+	gameID := flag.String("game", "", "id do jogo para buscar a seed no banco")
+	hand := flag.Int("hand", 0, "número da mão do jogo")
+	seed := flag.Int64("seed", 0, "seed do embaralhamento")
+	variant := flag.String("variant", string(game.Paulista), "variante do truco")
+	flag.Parse()
+
+	if *gameID != "" {
+		if err := godotenv.Load(); err != nil {
+			panic(err)
+		}
+
+		id, err := uuid.Parse(*gameID)
+		if err != nil {
+			panic(err)
+		}
+
+		ctx := context.Background()
+		pool, err := pgxpool.New(ctx, fmt.Sprintf(
+			"user=%s password=%s host=%s port=%s dbname=%s",
+			os.Getenv("TRUCO_DATABASE_USER"),
+			os.Getenv("TRUCO_DATABASE_PASSWORD"),
+			os.Getenv("TRUCO_DATABASE_HOST"),
+			os.Getenv("TRUCO_DATABASE_PORT"),
+			os.Getenv("TRUCO_DATABASE_NAME"),
+		))
+		if err != nil {
+			panic(err)
+		}
+		defer pool.Close()
+
+		q := pgstore.New(pool)
+		room, err := q.GetRoom(ctx, id)
+		if err != nil {
+			panic(err)
+		}
+		*variant = string(room.Variant)
+		if *hand == 0 {
+			*hand = int(room.Round)
+		}
+
+		commitment, err := q.GetRoomCommitment(ctx, pgstore.GetRoomCommitmentParams{RoomID: id, Round: int32(*hand)})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mão %d sem embaralhamento salvo: %v\n", *hand, err)
+			os.Exit(1)
+		}
+
+		var clientSeeds []string
+		if err := json.Unmarshal(commitment.ClientSeeds, &clientSeeds); err != nil {
+			panic(err)
+		}
+		if deck.CommitmentHash(commitment.ServerSeed) != commitment.Hash {
+			fmt.Fprintln(os.Stderr, deck.ErrCommitmentMismatch)
+			os.Exit(1)
+		}
+
+		// mãos anteriores à seed no commitment são refeitas pelas seeds guardadas
+		*seed = commitment.Seed
+		if *seed == 0 {
+			*seed = deck.CombineSeeds(commitment.ServerSeed, clientSeeds)
+		}
+		fmt.Println("mão:", *hand, "hash:", commitment.Hash)
+		fmt.Println("server seed:", commitment.ServerSeed, "client seeds:", clientSeeds)
+	}
+
+	if *seed == 0 {
+		fmt.Fprintln(os.Stderr, "informe -game ou -seed")
+		os.Exit(1)
+	}
+
+	rules, err := game.RulesFor(game.Variant(*variant))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("seed:", *seed, "variante:", rules.Variant)
+	for i, card := range deck.ShuffledCards(rules.Cards(), *seed) {
+		fmt.Printf("%2d %s\n", i+1, card.Code)
+	}
+}
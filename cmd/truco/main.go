@@ -7,8 +7,13 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/JoaoRafa19/truco-backend-go/internal/api"
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/moderation"
 	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
@@ -39,7 +44,81 @@ func main() {
 		panic(err)
 	}
 
-	handler := api.NewHandler(pgstore.New(pool))
+	q := pgstore.New(pool)
+
+	var decks deck.Provider
+	switch os.Getenv("TRUCO_DECK_PROVIDER") {
+	case "remote":
+		baseURL := os.Getenv("TRUCO_DECK_API_URL")
+		if baseURL == "" {
+			baseURL = deck.DefaultRemoteURL
+		}
+		decks = deck.NewRemoteProvider(baseURL, &http.Client{Timeout: 10 * time.Second})
+	default:
+		decks = deck.NewLocalProvider(deck.NewPgStore(q))
+	}
+
+	cfg := api.Config{TurnSeconds: api.DefaultTurnSeconds}
+	if seconds := os.Getenv("TRUCO_TURN_SECONDS"); seconds != "" {
+		if cfg.TurnSeconds, err = strconv.Atoi(seconds); err != nil {
+			panic(err)
+		}
+	}
+
+	cfg.ReconnectGrace = durationFromEnv("TRUCO_RECONNECT_GRACE", api.DefaultReconnectGrace)
+	cfg.PingInterval = durationFromEnv("TRUCO_WS_PING_INTERVAL", api.DefaultPingInterval)
+	cfg.PongTimeout = durationFromEnv("TRUCO_WS_PONG_TIMEOUT", api.DefaultPongTimeout)
+
+	cfg.MaxMessageSize = api.DefaultMaxMessageSize
+	if size := os.Getenv("TRUCO_WS_MAX_MESSAGE_SIZE"); size != "" {
+		if cfg.MaxMessageSize, err = strconv.ParseInt(size, 10, 64); err != nil {
+			panic(err)
+		}
+	}
+
+	if languages := os.Getenv("TRUCO_CHAT_FILTER"); languages != "" {
+		words, err := moderation.LoadLanguages(strings.Split(languages, ",")...)
+		if err != nil {
+			panic(err)
+		}
+		if path := os.Getenv("TRUCO_CHAT_FILTER_FILE"); path != "" {
+			file, err := os.Open(path)
+			if err != nil {
+				panic(err)
+			}
+			extra, err := moderation.ReadWords(file)
+			file.Close()
+			if err != nil {
+				panic(err)
+			}
+			words = append(words, extra...)
+		}
+		cfg.ChatFilter = moderation.NewFilter(words)
+	}
+
+	cfg.ChatRateLimit = api.DefaultChatRateLimit
+	if limit := os.Getenv("TRUCO_CHAT_RATE_LIMIT"); limit != "" {
+		if cfg.ChatRateLimit, err = strconv.Atoi(limit); err != nil {
+			panic(err)
+		}
+	}
+	cfg.ChatRateWindow = durationFromEnv("TRUCO_CHAT_RATE_WINDOW", api.DefaultChatRateWindow)
+	cfg.ChatMute = durationFromEnv("TRUCO_CHAT_MUTE", api.DefaultChatMute)
+
+	cfg.ReactionRateLimit = api.DefaultReactionRateLimit
+	if limit := os.Getenv("TRUCO_REACTION_RATE_LIMIT"); limit != "" {
+		if cfg.ReactionRateLimit, err = strconv.Atoi(limit); err != nil {
+			panic(err)
+		}
+	}
+	cfg.ReactionRateWindow = durationFromEnv("TRUCO_REACTION_RATE_WINDOW", api.DefaultReactionRateWindow)
+	cfg.SeedTimeout = durationFromEnv("TRUCO_SEED_TIMEOUT", api.DefaultSeedTimeout)
+
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
+	handler := api.NewHandler(q, decks, cfg)
 
 	go func() {
 		fmt.Println(
@@ -59,3 +138,17 @@ func main() {
 
 	<-quit
 }
+
+// durationFromEnv lê uma duração como "30s" da variável de ambiente
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
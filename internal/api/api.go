@@ -1,37 +1,103 @@
 package api
 
 import (
-	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/moderation"
 	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/jwtauth/v5"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
 type Room struct {
-	connections map[*websocket.Conn]context.CancelFunc
-	deck        deck.Deck
+	connections map[*websocket.Conn]*client
+	hand        *game.Hand
+	match       *game.Match
+	round       int32
+	// shuffle é o commitment publicado da próxima mão, esperando as seeds
+	shuffle *pendingShuffle
+	// turnTimeout é o tempo de cada jogada, zero desliga o relógio
+	turnTimeout time.Duration
+	clock       *turnClock
+	// seq numera os eventos enviados, history guarda os últimos para a reconexão
+	seq     int64
+	history []roomEvent
+	away    map[uuid.UUID]*awayPlayer
+	// chatSent e reactionSent são os horários dos últimos envios de cada
+	// jogador, para os limites do chat e das reações
+	chatSent     map[uuid.UUID][]time.Time
+	reactionSent map[uuid.UUID][]time.Time
+	muted        map[uuid.UUID]chatMute
+}
+
+// DefaultTurnSeconds é o tempo de cada jogada quando a sala não escolhe outro
+const DefaultTurnSeconds = 30
+
+// Config são as opções do servidor lidas do ambiente
+type Config struct {
+	// TurnSeconds é o tempo padrão de cada jogada nas salas novas
+	TurnSeconds int
+	// ReconnectGrace é quanto tempo a cadeira fica guardada depois que a conexão cai
+	ReconnectGrace time.Duration
+	// PingInterval é o intervalo entre os pings do servidor; zero desliga os pings
+	PingInterval time.Duration
+	// PongTimeout é quanto tempo a conexão pode ficar sem pong antes de cair
+	PongTimeout time.Duration
+	// MaxMessageSize é o tamanho máximo em bytes de uma mensagem do cliente
+	MaxMessageSize int64
+	// ChatFilter mascara os palavrões antes de gravar as mensagens; nil desliga
+	ChatFilter *moderation.Filter
+	// ChatRateLimit é quantas mensagens um jogador pode mandar em
+	// ChatRateWindow; zero desliga o limite
+	ChatRateLimit  int
+	ChatRateWindow time.Duration
+	// ChatMute é quanto tempo fica calado quem passa do limite
+	ChatMute time.Duration
+	// ReactionRateLimit é quantas reações um jogador pode mandar em
+	// ReactionRateWindow; zero desliga o limite
+	ReactionRateLimit  int
+	ReactionRateWindow time.Duration
+	// SeedTimeout é quanto a próxima mão espera as seeds dos jogadores depois
+	// do commitment; zero distribui sem esperar
+	SeedTimeout time.Duration
+}
+
+// Validate recusa combinações que derrubariam conexões saudáveis: o prazo do
+// pong precisa ser maior que o intervalo entre os pings
+func (cfg Config) Validate() error {
+	if cfg.PingInterval > 0 && cfg.PongTimeout > 0 && cfg.PongTimeout <= cfg.PingInterval {
+		return fmt.Errorf("pong timeout %s must be longer than ping interval %s", cfg.PongTimeout, cfg.PingInterval)
+	}
+	return nil
 }
 
 type apiHandler struct {
+	cfg       Config
 	q         *pgstore.Queries
+	decks     deck.Provider
 	r         *chi.Mux
 	tokenAuth *jwtauth.JWTAuth
 	upgrader  websocket.Upgrader
 	mu        *sync.Mutex
-	clients   map[string]Room
+	clients   map[string]*Room
 }
 
-func NewHandler(q *pgstore.Queries) http.Handler {
+func NewHandler(q *pgstore.Queries, decks deck.Provider, cfg Config) http.Handler {
 	h := apiHandler{
+		cfg:       cfg,
 		q:         q,
+		decks:     decks,
 		tokenAuth: jwtauth.New("HS256", []byte("go-truco"), nil),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
@@ -40,7 +106,7 @@ func NewHandler(q *pgstore.Queries) http.Handler {
 		},
 
 		mu:      &sync.Mutex{},
-		clients: make(map[string]Room),
+		clients: make(map[string]*Room),
 	}
 
 	r := chi.NewRouter()
@@ -57,6 +123,7 @@ func NewHandler(q *pgstore.Queries) http.Handler {
 	}))
 
 	r.Get("/echo/{message}/teste", h.handleEcho)
+	r.Get("/reactions", h.getReactions)
 
 	r.Route("/game", func(r chi.Router) {
 		r.Post("/", h.handleCreateGame)
@@ -67,7 +134,13 @@ func NewHandler(q *pgstore.Queries) http.Handler {
 			r.Use(jwtauth.Authenticator(h.tokenAuth))
 			r.Get("/connect", h.handleConnectToRoom) //ws
 			r.Get("/", h.getGameState)
+			r.Patch("/ready", h.handleReady)
 			r.Patch("/start", h.handleStartGame)
+			r.Get("/verify", h.handleVerifyShuffle)
+			r.Get("/messages", h.getChatMessages)
+			r.Post("/messages/{message_id}/report", h.handleReportMessage)
+			r.Post("/players/{player_id}/mute", h.handleMutePlayer)
+			r.Delete("/players/{player_id}/mute", h.handleUnmutePlayer)
 		})
 	})
 
@@ -76,21 +149,115 @@ func NewHandler(q *pgstore.Queries) http.Handler {
 	return h
 }
 
-func (h apiHandler) notifyClients(event []byte, roomId string) {
+// notifyClients envia o evento para todas as conexões da sala
+func (h apiHandler) notifyClients(roomID uuid.UUID, t EventType, payload any) {
+	h.notify(roomID, uuid.Nil, everyone, t, payload, true)
+}
+
+// notifyFrom envia para a sala um evento causado por um jogador, que aparece
+// como sender do envelope
+func (h apiHandler) notifyFrom(roomID, sender uuid.UUID, t EventType, payload any) {
+	h.notify(roomID, sender, everyone, t, payload, true)
+}
+
+// notifyLive envia o evento para a sala sem numerar nem guardar no histórico,
+// usado nos avisos do relógio que não fazem sentido reenviar
+func (h apiHandler) notifyLive(roomID uuid.UUID, t EventType, payload any) {
+	h.notify(roomID, uuid.Nil, everyone, t, payload, false)
+}
+
+// notifyPlayer envia o evento apenas para as conexões de um jogador, usado para
+// informações privadas como as cartas da mão
+func (h apiHandler) notifyPlayer(roomID, playerID uuid.UUID, t EventType, payload any) {
+	h.notify(roomID, uuid.Nil, audience{player: playerID, team: noTeam}, t, payload, true)
+}
+
+// notifyTeam envia o evento de um jogador apenas para as conexões do time dele;
+// adversários e quem está sem cadeira não recebem
+func (h apiHandler) notifyTeam(roomID, sender uuid.UUID, team int, t EventType, payload any) {
+	h.notify(roomID, sender, audience{team: team}, t, payload, true)
+}
+
+// notify envia o evento para as conexões da sala incluídas em to. Com record o
+// evento recebe um seq e vai para o histórico
+func (h apiHandler) notify(roomID, sender uuid.UUID, to audience, t EventType, payload any, record bool) {
+	envelope, err := newEnvelope(t, roomID, payload)
+	if err != nil {
+		slog.Error("failed to encode event", "error", err)
+		return
+	}
+	if sender != uuid.Nil {
+		envelope.Sender = &sender
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	room, ok := h.clients[roomId]
-	if !ok || len(room.connections) == 0 {
+	room, ok := h.clients[roomID.String()]
+	if !ok {
 		return
 	}
 
-	for conn, cancel := range room.connections {
-		if err := conn.WriteMessage(websocket.BinaryMessage, event); err != nil {
-			slog.Error("failed to send message to client", "error", err)
-			cancel()
+	var message []byte
+	if record {
+		message, err = room.record(to, &envelope)
+	} else {
+		message, err = json.Marshal(envelope)
+	}
+	if err != nil {
+		slog.Error("failed to encode event", "type", t, "error", err)
+		return
+	}
+
+	for _, client := range room.connections {
+		if to.includes(client) {
+			client.enqueue(envelope.Seq, message)
+		}
+	}
+}
+
+// notifyConn responde apenas para a conexão que enviou o evento
+func (h apiHandler) notifyConn(c *websocket.Conn, roomID uuid.UUID, t EventType, payload any) {
+	envelope, err := newEnvelope(t, roomID, payload)
+	if err == nil {
+		var message []byte
+		if message, err = json.Marshal(envelope); err == nil {
+			h.writeConn(c, roomID, message)
+			return
+		}
+	}
+	slog.Error("failed to encode event", "error", err)
+}
+
+// writeConn coloca a mensagem na fila da conexão
+func (h apiHandler) writeConn(c *websocket.Conn, roomID uuid.UUID, message []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room, ok := h.clients[roomID.String()]
+	if !ok {
+		return
+	}
+	if client, ok := room.connections[c]; ok {
+		client.enqueue(0, message)
+	}
+}
+
+// room retorna o estado em memória da sala, criando se necessário. Deve ser
+// chamado com h.mu travado
+func (h apiHandler) room(roomId string) *Room {
+	room, ok := h.clients[roomId]
+	if !ok {
+		room = &Room{
+			connections:  make(map[*websocket.Conn]*client),
+			away:         make(map[uuid.UUID]*awayPlayer),
+			chatSent:     make(map[uuid.UUID][]time.Time),
+			reactionSent: make(map[uuid.UUID][]time.Time),
+			muted:        make(map[uuid.UUID]chatMute),
 		}
+		h.clients[roomId] = room
 	}
+	return room
 }
 
 func (h apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
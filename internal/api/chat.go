@@ -0,0 +1,301 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// maxChatLength é o tamanho máximo de uma mensagem do chat, em caracteres
+	maxChatLength = 500
+	// chatHistorySize é quantas mensagens recentes o jogador recebe ao conectar
+	chatHistorySize = 50
+)
+
+// MessageEvent é uma mensagem de chat enviada pelo jogador. Sem canal a
+// mensagem vai para a sala inteira
+type MessageEvent struct {
+	Message string              `json:"message"`
+	Channel pgstore.ChatChannel `json:"channel,omitempty"`
+}
+
+func (e *MessageEvent) Validate() error {
+	switch e.Channel {
+	case "", pgstore.ChatChannelRoom, pgstore.ChatChannelTeam:
+	default:
+		return fmt.Errorf("channel must be %q or %q", pgstore.ChatChannelRoom, pgstore.ChatChannelTeam)
+	}
+	if strings.TrimSpace(e.Message) == "" {
+		return fmt.Errorf("message is required")
+	}
+	if utf8.RuneCountInString(e.Message) > maxChatLength {
+		return fmt.Errorf("message must have at most %d characters", maxChatLength)
+	}
+	return nil
+}
+
+// ChatPayload é uma mensagem do chat com o nome de quem enviou. Player e Name
+// ficam vazios quando o autor já saiu da sala
+type ChatPayload struct {
+	ID        uuid.UUID           `json:"id"`
+	Player    *uuid.UUID          `json:"player"`
+	Name      string              `json:"name"`
+	Message   string              `json:"message"`
+	Channel   pgstore.ChatChannel `json:"channel"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+var errNoTeam = &game.Error{Code: "no_team", Message: "only seated players can use the team channel"}
+
+// ChatHistoryPayload são as últimas mensagens da sala, da mais antiga para a
+// mais recente. Na reconexão elas podem repetir mensagens já reenviadas pelo
+// histórico de eventos; o cliente descarta pelo id
+type ChatHistoryPayload struct {
+	Messages []ChatPayload `json:"messages"`
+}
+
+// handleChatMessage grava a mensagem e envia para a sala, ou só para o time de
+// quem enviou no canal team
+func (h apiHandler) handleChatMessage(ctx context.Context, c *websocket.Conn, playerID, roomID uuid.UUID, body MessageEvent) {
+	channel := body.Channel
+	if channel == "" {
+		channel = pgstore.ChatChannelRoom
+	}
+
+	team := h.connTeam(c, roomID)
+	if channel == pgstore.ChatChannelTeam && team == noTeam {
+		h.sendError(c, roomID, errNoTeam)
+		return
+	}
+
+	if err := h.allowChat(roomID, playerID); err != nil {
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	text, _ := h.cfg.ChatFilter.Clean(strings.TrimSpace(body.Message))
+
+	message, err := h.q.CreateMessage(ctx, pgstore.CreateMessageParams{
+		RoomID:  roomID,
+		Message: text,
+		Player:  pgtype.UUID{Bytes: playerID, Valid: true},
+		Channel: channel,
+		Team:    int32(team),
+	})
+	if err != nil {
+		slog.Error("failed to save chat message", "error", err)
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	payload := newChatPayload(pgstore.GetRecentRoomMessagesRow(message))
+	if channel == pgstore.ChatChannelTeam {
+		h.notifyTeam(roomID, playerID, team, Message, payload)
+		return
+	}
+	h.notifyFrom(roomID, playerID, Message, payload)
+}
+
+// connTeam retorna o time da conexão guardado ao conectar
+func (h apiHandler) connTeam(c *websocket.Conn, roomID uuid.UUID) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if room, ok := h.clients[roomID.String()]; ok {
+		if client, ok := room.connections[c]; ok {
+			return client.team
+		}
+	}
+	return noTeam
+}
+
+// sendChatHistory envia para a conexão as mensagens recentes da sala que o
+// time dela pode ver
+func (h apiHandler) sendChatHistory(ctx context.Context, c *websocket.Conn, roomID uuid.UUID, team int) {
+	recent, err := h.q.GetRecentRoomMessages(ctx, pgstore.GetRecentRoomMessagesParams{
+		RoomID: roomID,
+		Team:   int32(team),
+		Limit:  chatHistorySize,
+	})
+	if err != nil {
+		slog.Error("failed to load chat history", "error", err)
+		return
+	}
+
+	// a consulta traz as mais recentes primeiro
+	messages := make([]ChatPayload, len(recent))
+	for i, message := range recent {
+		messages[len(recent)-1-i] = newChatPayload(message)
+	}
+
+	h.notifyConn(c, roomID, ChatHistory, ChatHistoryPayload{Messages: messages})
+}
+
+// newChatPayload converte uma mensagem lida do banco. As consultas do chat
+// retornam todas as mesmas colunas
+func newChatPayload(message pgstore.GetRecentRoomMessagesRow) ChatPayload {
+	payload := ChatPayload{
+		ID:        message.ID,
+		Name:      message.PlayerName,
+		Message:   message.Message,
+		Channel:   message.Channel,
+		CreatedAt: message.CreatedAt.Time,
+	}
+	if message.Player.Valid {
+		player := uuid.UUID(message.Player.Bytes)
+		payload.Player = &player
+	}
+	return payload
+}
+
+const maxChatPage = 100
+
+// chatPage é uma página do histórico do chat em ordem cronológica. HasMore
+// indica que há mensagens além da página na direção pedida
+type chatPage struct {
+	Messages []ChatPayload `json:"messages"`
+	HasMore  bool          `json:"has_more"`
+}
+
+// getChatMessages pagina o chat da sala. Sem cursor retorna as mensagens mais
+// recentes; before volta a partir da mensagem informada e after avança
+func (h apiHandler) getChatMessages(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return
+	}
+
+	playerID, _, err := h.GetPlayerAndRoom(r, w, roomID)
+	if err != nil {
+		return
+	}
+
+	team, err := h.playerTeam(r.Context(), roomID, playerID)
+	if err != nil {
+		slog.Error("GetChatMessages", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := chatHistorySize
+	if raw := query.Get("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxChatPage {
+			http.Error(w, fmt.Sprintf("limit must be between 1 and %d", maxChatPage), http.StatusBadRequest)
+			return
+		}
+	}
+
+	before, after := query.Get("before"), query.Get("after")
+	if before != "" && after != "" {
+		http.Error(w, "use either before or after", http.StatusBadRequest)
+		return
+	}
+
+	cursor, ok := h.chatCursor(w, r, roomID, team, before+after)
+	if !ok {
+		return
+	}
+
+	// busca uma mensagem a mais para saber se há outra página
+	fetch := int32(limit + 1)
+	var rows []pgstore.GetRecentRoomMessagesRow
+	switch {
+	case after != "":
+		var page []pgstore.GetRoomMessagesAfterRow
+		page, err = h.q.GetRoomMessagesAfter(r.Context(), pgstore.GetRoomMessagesAfterParams{
+			RoomID: roomID, Team: int32(team), CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: fetch,
+		})
+		for _, row := range page {
+			rows = append(rows, pgstore.GetRecentRoomMessagesRow(row))
+		}
+	case before != "":
+		var page []pgstore.GetRoomMessagesBeforeRow
+		page, err = h.q.GetRoomMessagesBefore(r.Context(), pgstore.GetRoomMessagesBeforeParams{
+			RoomID: roomID, Team: int32(team), CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: fetch,
+		})
+		for _, row := range page {
+			rows = append(rows, pgstore.GetRecentRoomMessagesRow(row))
+		}
+	default:
+		rows, err = h.q.GetRecentRoomMessages(r.Context(), pgstore.GetRecentRoomMessagesParams{
+			RoomID: roomID, Team: int32(team), Limit: fetch,
+		})
+	}
+	if err != nil {
+		slog.Error("GetChatMessages", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	result := chatPage{Messages: make([]ChatPayload, 0, limit), HasMore: len(rows) > limit}
+	if result.HasMore {
+		rows = rows[:limit]
+	}
+	for _, row := range rows {
+		result.Messages = append(result.Messages, newChatPayload(row))
+	}
+	// before e a página inicial vêm da mais recente para a mais antiga
+	if after == "" {
+		slices.Reverse(result.Messages)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	returnData(data, w)
+}
+
+// chatCursor busca a mensagem usada como cursor, que precisa ser da sala e
+// visível para o time. Sem cursor retorna uma mensagem vazia
+func (h apiHandler) chatCursor(w http.ResponseWriter, r *http.Request, roomID uuid.UUID, team int, raw string) (pgstore.ChatMessage, bool) {
+	if raw == "" {
+		return pgstore.ChatMessage{}, true
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		http.Error(w, "invalid cursor", http.StatusBadRequest)
+		return pgstore.ChatMessage{}, false
+	}
+
+	cursor, err := h.q.GetMessage(r.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !chatVisible(cursor, roomID, team)) {
+		http.Error(w, "invalid cursor", http.StatusBadRequest)
+		return pgstore.ChatMessage{}, false
+	}
+	if err != nil {
+		slog.Error("GetChatMessages", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return pgstore.ChatMessage{}, false
+	}
+	return cursor, true
+}
+
+func chatVisible(message pgstore.ChatMessage, roomID uuid.UUID, team int) bool {
+	if message.RoomID != roomID {
+		return false
+	}
+	return message.Channel == pgstore.ChatChannelRoom || int(message.Team) == team
+}
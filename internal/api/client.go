@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// sendQueueSize cabe o histórico inteiro reenviado na reconexão e mais uma folga
+	sendQueueSize = maxHistory + 64
+	writeTimeout  = 10 * time.Second
+
+	DefaultPingInterval   = 30 * time.Second
+	DefaultPongTimeout    = 60 * time.Second
+	DefaultMaxMessageSize = 4096
+)
+
+// client é uma conexão de um jogador. Só a goroutine de escrita escreve no
+// WebSocket; os eventos chegam pela fila send, então um celular lento não
+// segura o h.mu nem as outras salas
+type client struct {
+	playerID uuid.UUID
+	conn     *websocket.Conn
+	cancel   context.CancelFunc
+	send     chan outbound
+	// written é o seq do último evento que a goroutine de escrita conseguiu
+	// escrever no WebSocket, de onde a reconexão continua
+	written atomic.Int64
+	// team é o time do jogador na mesa, noTeam para quem não tem cadeira
+	team int
+	// pongTimeout é o prazo de leitura renovado a cada mensagem e a cada pong;
+	// zero quando a conexão não tem prazo
+	pongTimeout time.Duration
+}
+
+// outbound é uma mensagem na fila da conexão. seq é zero nos eventos que não
+// vão para o histórico
+type outbound struct {
+	seq     int64
+	message []byte
+}
+
+func newClient(conn *websocket.Conn, playerID uuid.UUID, team int, cancel context.CancelFunc) *client {
+	return &client{
+		playerID: playerID,
+		conn:     conn,
+		cancel:   cancel,
+		send:     make(chan outbound, sendQueueSize),
+		team:     team,
+	}
+}
+
+// enqueue coloca a mensagem na fila sem bloquear. Se a fila estiver cheia o
+// cliente não está dando conta e é desconectado; ao voltar ele pode pedir os
+// eventos perdidos com last_seq. Deve ser chamado com h.mu travado
+func (cl *client) enqueue(seq int64, message []byte) bool {
+	select {
+	case cl.send <- outbound{seq: seq, message: message}:
+		return true
+	default:
+		slog.Warn("send queue full, dropping client", "player", cl.playerID)
+		cl.cancel()
+		return false
+	}
+}
+
+// keepAlive limita o tamanho das mensagens do cliente e derruba a conexão que
+// passar de pongTimeout sem mandar nada nem responder aos pings. O erro aparece
+// no ReadMessage e a conexão sai da sala pelo disconectClient. Sem pings o
+// cliente não tem o que responder, então a conexão fica sem prazo
+func (cl *client) keepAlive(maxMessageSize int64, pingInterval, pongTimeout time.Duration) {
+	if maxMessageSize > 0 {
+		cl.conn.SetReadLimit(maxMessageSize)
+	}
+	if pingInterval <= 0 || pongTimeout <= 0 {
+		return
+	}
+
+	cl.pongTimeout = pongTimeout
+	cl.extendDeadline()
+	cl.conn.SetPongHandler(func(string) error {
+		return cl.extendDeadline()
+	})
+}
+
+// extendDeadline renova o prazo de leitura da conexão
+func (cl *client) extendDeadline() error {
+	if cl.pongTimeout <= 0 {
+		return nil
+	}
+	return cl.conn.SetReadDeadline(time.Now().Add(cl.pongTimeout))
+}
+
+// writePump escreve as mensagens da fila e os pings até a conexão ser encerrada
+func (cl *client) writePump(ctx context.Context, pingInterval time.Duration) {
+	var ping <-chan time.Time
+	if pingInterval > 0 {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			cl.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(writeTimeout))
+			return
+		case <-ping:
+			if err := cl.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				slog.Info("failed to ping client", "error", err)
+				cl.cancel()
+				return
+			}
+		case out := <-cl.send:
+			cl.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := cl.conn.WriteMessage(websocket.BinaryMessage, out.message); err != nil {
+				slog.Error("failed to send message to client", "error", err)
+				cl.cancel()
+				return
+			}
+			if out.seq > 0 {
+				cl.written.Store(out.seq)
+			}
+		}
+	}
+}
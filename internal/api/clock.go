@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Intervalo entre os avisos de tempo restante
+const clockTick = 5 * time.Second
+
+// turnClock é o relógio da jogada atual. Fica na sala e não na conexão, então
+// continua correndo se o jogador cair e voltar
+type turnClock struct {
+	player   uuid.UUID
+	action   game.TimeoutAction
+	deadline time.Time
+	timer    *time.Timer
+	stop     chan struct{}
+}
+
+// ClockPayload é o tempo restante da jogada. Timeout indica que o tempo
+// acabou e o servidor vai jogar pelo jogador
+type ClockPayload struct {
+	Player    uuid.UUID          `json:"player"`
+	Action    game.TimeoutAction `json:"action"`
+	Deadline  time.Time          `json:"deadline"`
+	Remaining int                `json:"remaining"`
+	Timeout   bool               `json:"timeout,omitempty"`
+}
+
+func (clock *turnClock) payload() ClockPayload {
+	return ClockPayload{
+		Player:    clock.player,
+		Action:    clock.action,
+		Deadline:  clock.deadline,
+		Remaining: clock.remaining(),
+	}
+}
+
+// remaining são os segundos que faltam para o tempo acabar
+func (clock *turnClock) remaining() int {
+	return max(int(math.Ceil(time.Until(clock.deadline).Seconds())), 0)
+}
+
+// resetClock começa o relógio de quem a mesa está esperando. Deve ser chamado
+// depois de toda ação que muda a vez
+func (h apiHandler) resetClock(roomID uuid.UUID) {
+	h.mu.Lock()
+	room := h.room(roomID.String())
+	stopClock(room)
+
+	if room.hand == nil || room.turnTimeout <= 0 {
+		h.mu.Unlock()
+		return
+	}
+	player, action, ok := room.hand.OnTheClock()
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+
+	clock := &turnClock{
+		player:   player,
+		action:   action,
+		deadline: time.Now().Add(room.turnTimeout),
+		stop:     make(chan struct{}),
+	}
+	clock.timer = time.AfterFunc(room.turnTimeout, func() { h.expireClock(roomID, clock) })
+	room.clock = clock
+	h.mu.Unlock()
+
+	h.notifyClients(roomID, TurnClock, clock.payload())
+	go h.tickClock(roomID, clock)
+}
+
+// stopClock para o relógio da sala. Deve ser chamado com h.mu travado
+func stopClock(room *Room) {
+	if room.clock == nil {
+		return
+	}
+	room.clock.timer.Stop()
+	close(room.clock.stop)
+	room.clock = nil
+}
+
+func (h apiHandler) tickClock(roomID uuid.UUID, clock *turnClock) {
+	ticker := time.NewTicker(clockTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-clock.stop:
+			return
+		case <-ticker.C:
+			h.notifyLive(roomID, TurnClock, clock.payload())
+		}
+	}
+}
+
+// sendClock envia o relógio atual para uma conexão, usado quando o jogador
+// se conecta no meio da jogada
+func (h apiHandler) sendClock(c *websocket.Conn, roomID uuid.UUID) {
+	h.mu.Lock()
+	room, ok := h.clients[roomID.String()]
+	if !ok || room.clock == nil {
+		h.mu.Unlock()
+		return
+	}
+	payload := room.clock.payload()
+	h.mu.Unlock()
+
+	h.notifyConn(c, roomID, TurnClock, payload)
+}
+
+// expireClock joga pelo jogador quando o tempo acaba: a carta mais fraca na
+// vez de jogar, ou corre do pedido pendente
+func (h apiHandler) expireClock(roomID uuid.UUID, clock *turnClock) {
+	h.mu.Lock()
+	room, ok := h.clients[roomID.String()]
+	if !ok || room.clock != clock {
+		// o jogador agiu antes do tempo acabar
+		h.mu.Unlock()
+		return
+	}
+	stopClock(room)
+
+	var card string
+	if clock.action == game.TimeoutPlay {
+		var err error
+		if card, err = room.hand.LowestCard(clock.player); err != nil {
+			h.mu.Unlock()
+			slog.Error("failed to pick card on timeout", "error", err)
+			return
+		}
+	}
+	h.mu.Unlock()
+
+	timeout := clock.payload()
+	timeout.Timeout = true
+	h.notifyClients(roomID, TurnClock, timeout)
+
+	// o relógio não pertence a nenhuma requisição
+	ctx := context.Background()
+	switch clock.action {
+	case game.TimeoutPlay:
+		h.handlePlayCard(ctx, nil, clock.player, roomID, CardEvent{Card: card})
+	case game.TimeoutDecline:
+		h.handleRaiseResponse(ctx, nil, clock.player, roomID, ResponseEvent{Answer: game.Decline})
+	case game.TimeoutDeclineEnvido:
+		h.handleEnvidoResponse(ctx, nil, clock.player, roomID, ResponseEvent{Answer: game.Decline})
+	case game.TimeoutRun:
+		run := false
+		h.handleElevenDecision(ctx, nil, clock.player, roomID, ElevenDecisionEvent{Play: &run})
+	}
+}
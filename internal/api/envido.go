@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Pedido de cada evento de envido
+var envidoCalls = map[EventType]game.EnvidoCall{
+	Envido:            game.CallEnvido,
+	RealEnvido:        game.CallRealEnvido,
+	FaltaEnvido:       game.CallFaltaEnvido,
+	Flor:              game.CallFlor,
+	ContraFlor:        game.CallContraFlor,
+	ContraFlorAlResto: game.CallContraFlorAlResto,
+}
+
+// EnvidoPayload é um pedido de envido ou flor, ou a resposta a ele
+type EnvidoPayload struct {
+	Player uuid.UUID   `json:"player"`
+	Team   int         `json:"team"`
+	Call   string      `json:"call,omitempty"`
+	Answer string      `json:"answer,omitempty"`
+	Value  int         `json:"value"`
+	Envido game.Envido `json:"envido"`
+}
+
+// EnvidoResultPayload é o canto dos pontos e o time que levou o envido
+type EnvidoResultPayload struct {
+	Winner       int                `json:"winner"`
+	Points       int                `json:"points"`
+	Flor         bool               `json:"flor"`
+	Declarations []game.Declaration `json:"declarations"`
+	Score        [2]int             `json:"score"`
+}
+
+// handleEnvido recebe um pedido de envido, real envido, falta envido, flor ou
+// contraflor. A flor sem flor do outro lado é resolvida na hora; os demais
+// esperam a resposta do adversário
+func (h apiHandler) handleEnvido(ctx context.Context, c *websocket.Conn, playerID, roomID uuid.UUID, eventType EventType) {
+	call := envidoCalls[eventType]
+
+	h.mu.Lock()
+	hand := h.room(roomID.String()).hand
+	if hand == nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, errNoHandInProgress)
+		return
+	}
+
+	if err := hand.CallEnvido(playerID, call); err != nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	seat, _ := hand.Seat(playerID)
+	payload := EnvidoPayload{
+		Player: playerID,
+		Team:   game.Team(seat),
+		Call:   string(call),
+		Value:  hand.EnvidoValue(hand.Envido.Calls),
+		Envido: hand.Envido.Clone(),
+	}
+	done := hand.Envido.Done
+	if done {
+		payload.Value = hand.Envido.Points
+	}
+	h.mu.Unlock()
+
+	h.notifyFrom(roomID, playerID, eventType, payload)
+
+	if done {
+		h.finishEnvido(ctx, roomID)
+		return
+	}
+	h.resetClock(roomID)
+}
+
+func (h apiHandler) handleEnvidoResponse(ctx context.Context, c *websocket.Conn, playerID, roomID uuid.UUID, body ResponseEvent) {
+	h.mu.Lock()
+	hand := h.room(roomID.String()).hand
+	if hand == nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, errNoHandInProgress)
+		return
+	}
+
+	if err := hand.RespondEnvido(playerID, body.Answer); err != nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	seat, _ := hand.Seat(playerID)
+	payload := EnvidoPayload{
+		Player: playerID,
+		Team:   game.Team(seat),
+		Answer: string(body.Answer),
+		Value:  hand.Envido.Points,
+		Envido: hand.Envido.Clone(),
+	}
+	h.mu.Unlock()
+
+	h.notifyFrom(roomID, playerID, EnvidoResponse, payload)
+
+	h.finishEnvido(ctx, roomID)
+}
+
+// finishEnvido soma os pontos do envido ao placar, independente do valor da mão,
+// e avisa a sala com o canto de cada jogador. Se os pontos encerrarem a partida
+// a mão também é encerrada
+func (h apiHandler) finishEnvido(ctx context.Context, roomID uuid.UUID) {
+	h.mu.Lock()
+	room := h.room(roomID.String())
+	hand, match := room.hand, room.match
+	if hand == nil || match == nil || match.Finished || !hand.Envido.Done {
+		// a partida acabou por W.O. ou a mão mudou enquanto o envido era processado
+		h.mu.Unlock()
+		return
+	}
+	match.Add(hand.Envido.Winner, hand.Envido.Points)
+	if match.Finished {
+		hand.Done = true
+	}
+	envido := hand.Envido.Clone()
+	score := *match
+	h.mu.Unlock()
+
+	h.notifyClients(roomID, EnvidoResult, EnvidoResultPayload{
+		Winner:       envido.Winner,
+		Points:       envido.Points,
+		Flor:         envido.Flor,
+		Declarations: envido.Declarations,
+		Score:        score.Score,
+	})
+
+	if score.Finished {
+		h.finishHand(ctx, roomID)
+		return
+	}
+	h.saveScore(ctx, roomID, score)
+	h.resetClock(roomID)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Quantidade de eventos guardados por sala para reenviar na reconexão
+const maxHistory = 256
+
+// noTeam é o time de quem não tem cadeira na mesa
+const noTeam = -1
+
+// audience são as conexões que podem receber um evento: um jogador, um time ou,
+// no valor de everyone, a sala inteira
+type audience struct {
+	player uuid.UUID
+	team   int
+}
+
+var everyone = audience{team: noTeam}
+
+func (a audience) includes(cl *client) bool {
+	if a.player != uuid.Nil && a.player != cl.playerID {
+		return false
+	}
+	return a.team == noTeam || a.team == cl.team
+}
+
+// roomEvent é um evento enviado para a sala e quem pode recebê-lo
+type roomEvent struct {
+	seq     int64
+	to      audience
+	message []byte
+}
+
+// record numera o evento e guarda no histórico da sala, retornando a mensagem
+// pronta para envio. Os números crescem por sala; um jogador vê buracos na
+// sequência onde estão os eventos privados dos outros. Um evento que não pode
+// ser codificado não entra no histórico nem consome o número. Deve ser chamado
+// com h.mu travado
+func (room *Room) record(to audience, envelope *Envelope) ([]byte, error) {
+	envelope.Seq = room.seq + 1
+	message, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	room.seq++
+
+	room.history = append(room.history, roomEvent{seq: room.seq, to: to, message: message})
+	if len(room.history) > maxHistory {
+		room.history = room.history[len(room.history)-maxHistory:]
+	}
+	return message, nil
+}
+
+// missedEvents retorna os eventos da conexão enviados depois de lastSeq. ok é
+// falso quando parte dos eventos já saiu do histórico ou quando lastSeq é de
+// uma sala que não existe mais em memória (restart do servidor), e o cliente
+// precisa buscar o estado completo da mesa. Deve ser chamado com h.mu travado
+func (room *Room) missedEvents(cl *client, lastSeq int64) ([]roomEvent, bool) {
+	if lastSeq > room.seq {
+		return nil, false
+	}
+	if len(room.history) > 0 && room.history[0].seq > lastSeq+1 {
+		return nil, false
+	}
+
+	var missed []roomEvent
+	for _, event := range room.history {
+		if event.seq <= lastSeq {
+			continue
+		}
+		if event.to.includes(cl) {
+			missed = append(missed, event)
+		}
+	}
+	return missed, true
+}
+
+// replay coloca na fila da conexão os eventos perdidos antes dos eventos ao
+// vivo. Deve ser chamado com h.mu travado, assim nenhum evento novo entra no
+// meio do reenvio
+func (room *Room) replay(cl *client, roomID uuid.UUID, lastSeq int64) error {
+	missed, ok := room.missedEvents(cl, lastSeq)
+	if !ok {
+		envelope, err := newEnvelope(Resync, roomID, ResyncPayload{Seq: room.seq})
+		if err != nil {
+			return err
+		}
+		message, err := json.Marshal(envelope)
+		if err != nil {
+			return err
+		}
+		// com o estado buscado de novo o cliente está em dia até room.seq
+		cl.enqueue(room.seq, message)
+		return nil
+	}
+
+	for _, event := range missed {
+		if !cl.enqueue(event.seq, event.message) {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5"
+)
+
+type shuffleProof struct {
+	Round       int32    `json:"round"`
+	Hash        string   `json:"hash"`
+	ServerSeed  string   `json:"server_seed"`
+	ClientSeeds []string `json:"client_seeds"`
+	Valid       bool     `json:"valid"`
+	Cards       []string `json:"cards,omitempty"`
+}
+
+// maxClientSeed é o tamanho máximo da seed de um jogador
+const maxClientSeed = 64
+
+// DefaultSeedTimeout é quanto a próxima mão espera as seeds dos jogadores
+const DefaultSeedTimeout = 5 * time.Second
+
+var errNoCommitment = &game.Error{Code: "no_commitment", Message: "no shuffle commitment open for this round"}
+
+// pendingShuffle é o commitment já publicado de uma mão que ainda não foi
+// distribuída e as seeds que os jogadores mandaram para ela
+type pendingShuffle struct {
+	round int32
+	// ready é fechado quando o commitment foi gravado ou falhou com err
+	ready      chan struct{}
+	err        error
+	commitment pgstore.ShuffleCommitment
+	seeds      map[uuid.UUID]string
+	// players são as cadeiras que a mão espera; nil antes da primeira mão, que
+	// começa quando o host manda
+	players []uuid.UUID
+	timer   *time.Timer
+	dealing bool
+}
+
+// complete diz se todos os jogadores esperados já mandaram a seed
+func (p *pendingShuffle) complete() bool {
+	for _, player := range p.players {
+		if _, ok := p.seeds[player]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ShuffleCommitPayload é o hash da seed do servidor para a mão round. Ele é
+// publicado antes das seeds dos jogadores serem aceitas
+type ShuffleCommitPayload struct {
+	Round int32  `json:"round"`
+	Hash  string `json:"hash"`
+}
+
+// SeedPayload avisa a sala que o jogador do envelope mandou a seed da mão
+type SeedPayload struct {
+	Round int32 `json:"round"`
+}
+
+// prepareShuffle publica o commitment da mão round. O commitment pendente da
+// sala é reaproveitado, inclusive o que ficou no banco depois de um restart
+func (h apiHandler) prepareShuffle(ctx context.Context, roomID uuid.UUID, round int32) (*pendingShuffle, error) {
+	h.mu.Lock()
+	room := h.room(roomID.String())
+	if pending := room.shuffle; pending != nil && pending.round == round {
+		h.mu.Unlock()
+		<-pending.ready
+		return pending, pending.err
+	}
+	// a rodada fica reservada para que duas chamadas não gerem dois commitments
+	pending := &pendingShuffle{round: round, ready: make(chan struct{}), seeds: make(map[uuid.UUID]string)}
+	room.shuffle = pending
+	h.mu.Unlock()
+
+	commitment, err := h.q.GetPendingCommitment(ctx, pgstore.GetPendingCommitmentParams{RoomID: roomID, Round: round})
+	if errors.Is(err, pgx.ErrNoRows) {
+		commitment, err = h.createCommitment(ctx, roomID, round)
+	}
+
+	h.mu.Lock()
+	pending.commitment, pending.err = commitment, err
+	if err != nil && room.shuffle == pending {
+		room.shuffle = nil
+	}
+	h.mu.Unlock()
+	close(pending.ready)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h.notifyClients(roomID, ShuffleCommit, ShuffleCommitPayload{Round: round, Hash: commitment.Hash})
+	return pending, nil
+}
+
+func (h apiHandler) createCommitment(ctx context.Context, roomID uuid.UUID, round int32) (pgstore.ShuffleCommitment, error) {
+	commitment, err := deck.NewCommitment()
+	if err != nil {
+		return pgstore.ShuffleCommitment{}, err
+	}
+
+	return h.q.CreateShuffleCommitment(ctx, pgstore.CreateShuffleCommitmentParams{
+		RoomID:      roomID,
+		Round:       round,
+		Hash:        commitment.Hash,
+		ServerSeed:  commitment.ServerSeed,
+		ClientSeeds: []byte("[]"),
+	})
+}
+
+// addClientSeed guarda a seed do jogador para a mão round e diz se era a
+// última que a mão esperava
+func (h apiHandler) addClientSeed(roomID, playerID uuid.UUID, round int32, seed string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pending := h.room(roomID.String()).shuffle
+	if pending == nil || pending.round != round || pending.dealing {
+		return false, errNoCommitment
+	}
+	select {
+	case <-pending.ready:
+	default:
+		return false, errNoCommitment
+	}
+	if pending.err != nil {
+		return false, errNoCommitment
+	}
+
+	pending.seeds[playerID] = seed
+	return pending.players != nil && pending.complete(), nil
+}
+
+// handleClientSeed recebe pelo WebSocket a seed do jogador para a próxima mão
+func (h apiHandler) handleClientSeed(c *websocket.Conn, playerID, roomID uuid.UUID, body SeedEvent) {
+	last, err := h.addClientSeed(roomID, playerID, body.Round, body.Seed)
+	if err != nil {
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	h.notifyFrom(roomID, playerID, Seed, SeedPayload{Round: body.Round})
+
+	if last {
+		h.dealNext(roomID, body.Round)
+	}
+}
+
+// waitSeeds espera as seeds dos jogadores antes de distribuir a mão round. A
+// mão sai quando todos mandaram a seed ou quando SeedTimeout acaba
+func (h apiHandler) waitSeeds(roomID uuid.UUID, round int32, players []uuid.UUID) {
+	h.mu.Lock()
+	pending := h.room(roomID.String()).shuffle
+	if pending != nil && pending.round == round && h.cfg.SeedTimeout > 0 {
+		pending.players = players
+		if !pending.complete() {
+			pending.timer = time.AfterFunc(h.cfg.SeedTimeout, func() { h.dealNext(roomID, round) })
+			h.mu.Unlock()
+			return
+		}
+	}
+	h.mu.Unlock()
+
+	h.dealNext(roomID, round)
+}
+
+// dealNext distribui a mão round uma única vez, seja pelo prazo ou pela última seed
+func (h apiHandler) dealNext(roomID uuid.UUID, round int32) {
+	h.mu.Lock()
+	room, ok := h.clients[roomID.String()]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	if pending := room.shuffle; pending != nil && pending.round == round {
+		if pending.dealing {
+			h.mu.Unlock()
+			return
+		}
+		pending.dealing = true
+		stopShuffle(room)
+	}
+	h.mu.Unlock()
+
+	start, hand, err := h.startHand(context.Background(), roomID, "start hand")
+	if err != nil {
+		slog.Error("failed to start next hand", "error", err)
+		return
+	}
+	h.announceHand(roomID, start, hand)
+}
+
+// stopShuffle para o prazo das seeds da sala. Deve ser chamado com h.mu travado
+func stopShuffle(room *Room) {
+	if room.shuffle != nil && room.shuffle.timer != nil {
+		room.shuffle.timer.Stop()
+	}
+}
+
+// commitShuffle reembaralha o baralho da sala combinando a seed do servidor,
+// publicada antes, com as seeds que os jogadores sentados mandaram, na ordem
+// das cadeiras. Retorna o hash do commitment usado. Providers sem suporte a
+// seed embaralham normalmente e o commitment é descartado
+func (h apiHandler) commitShuffle(ctx context.Context, room pgstore.Game, players []uuid.UUID) (string, error) {
+	pending, err := h.prepareShuffle(ctx, room.ID, room.Round)
+	if err != nil {
+		return "", err
+	}
+
+	h.mu.Lock()
+	gameRoom := h.room(room.ID.String())
+	if gameRoom.shuffle == pending {
+		stopShuffle(gameRoom)
+		gameRoom.shuffle = nil
+	}
+	clientSeeds := make([]string, 0, len(players))
+	for _, player := range players {
+		if seed, ok := pending.seeds[player]; ok {
+			clientSeeds = append(clientSeeds, seed)
+		}
+	}
+	h.mu.Unlock()
+
+	commitment := pending.commitment
+	shuffled, err := h.decks.Reshuffle(ctx, room.DeckID, deck.CombineSeeds(commitment.ServerSeed, clientSeeds))
+	if errors.Is(err, deck.ErrSeedUnsupported) {
+		if err := h.q.DeleteShuffleCommitment(ctx, commitment.ID); err != nil {
+			return "", err
+		}
+		_, err = h.decks.Reshuffle(ctx, room.DeckID, 0)
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+
+	seeds, err := json.Marshal(clientSeeds)
+	if err != nil {
+		return "", err
+	}
+
+	// a seed fica no commitment da mão para que cada mão possa ser refeita
+	if err := h.q.SetCommitmentSeeds(ctx, pgstore.SetCommitmentSeedsParams{
+		ID:          commitment.ID,
+		ClientSeeds: seeds,
+		Seed:        shuffled.Seed,
+	}); err != nil {
+		return "", err
+	}
+
+	return commitment.Hash, nil
+}
+
+// ShuffleRevealPayload é a seed de uma mão encerrada, para o cliente conferir
+type ShuffleRevealPayload struct {
+	Proof shuffleProof `json:"proof"`
+}
+
+// revealShuffle publica as seeds das mãos da sala encerradas até round. O
+// commitment da mão seguinte, já publicado, continua guardado
+func (h apiHandler) revealShuffle(ctx context.Context, roomID uuid.UUID, round int32) error {
+	revealed, err := h.q.RevealRoomCommitments(ctx, pgstore.RevealRoomCommitmentsParams{RoomID: roomID, Round: round})
+	if err != nil {
+		return err
+	}
+
+	for _, commitment := range revealed {
+		proof, err := newShuffleProof(commitment, nil)
+		if err != nil {
+			return err
+		}
+
+		h.notifyClients(roomID, ShuffleReveal, ShuffleRevealPayload{Proof: proof})
+	}
+
+	return nil
+}
+
+// newShuffleProof refaz o embaralhamento com as cartas de composition; sem
+// composição apenas o hash é conferido
+func newShuffleProof(commitment pgstore.ShuffleCommitment, composition []deck.Card) (shuffleProof, error) {
+	var clientSeeds []string
+	if err := json.Unmarshal(commitment.ClientSeeds, &clientSeeds); err != nil {
+		return shuffleProof{}, err
+	}
+
+	proof := shuffleProof{
+		Round:       commitment.Round,
+		Hash:        commitment.Hash,
+		ServerSeed:  commitment.ServerSeed,
+		ClientSeeds: clientSeeds,
+	}
+
+	cards, err := deck.VerifyShuffle(composition, commitment.ServerSeed, commitment.Hash, clientSeeds)
+	if err != nil {
+		return proof, nil
+	}
+
+	proof.Valid = true
+	for _, card := range cards {
+		proof.Cards = append(proof.Cards, card.Code)
+	}
+	return proof, nil
+}
+
+func (h apiHandler) handleVerifyShuffle(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return
+	}
+
+	if _, _, err := h.GetPlayerAndRoom(r, w, roomID); err != nil {
+		return
+	}
+
+	room, err := h.q.GetRoom(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusNotFound)
+		return
+	}
+
+	rules, err := game.RulesFor(game.Variant(room.Variant))
+	if err != nil {
+		slog.Error("VerifyShuffle", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	commitments, err := h.q.GetRevealedCommitments(r.Context(), roomID)
+	if err != nil {
+		slog.Error("VerifyShuffle", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	proofs := make([]shuffleProof, 0, len(commitments))
+	for _, commitment := range commitments {
+		proof, err := newShuffleProof(commitment, rules.Cards())
+		if err != nil {
+			slog.Error("VerifyShuffle", "error", err)
+			returnError(w, http.StatusInternalServerError)
+			return
+		}
+		proofs = append(proofs, proof)
+	}
+
+	result, err := json.Marshal(proofs)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	returnData(result, w)
+}
@@ -0,0 +1,488 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// StartGamePayload é o início de uma mão. Stage é "start game" na primeira mão
+// e "start hand" nas seguintes
+type StartGamePayload struct {
+	Stage      string       `json:"stage"`
+	Commitment string       `json:"commitment,omitempty"`
+	Round      int32        `json:"round"`
+	Vira       *deck.Card   `json:"vira,omitempty"`
+	Score      [2]int       `json:"score"`
+	Special    game.Special `json:"special,omitempty"`
+}
+
+// DealPayload são as cartas do jogador. Na mão de ferro vem só a quantidade
+type DealPayload struct {
+	Round         int32                     `json:"round"`
+	Cards         []deck.Card               `json:"cards"`
+	CardCount     int                       `json:"card_count"`
+	PartnersCards map[uuid.UUID][]deck.Card `json:"partners_cards,omitempty"`
+	Vira          *deck.Card                `json:"vira,omitempty"`
+	Special       game.Special              `json:"special,omitempty"`
+}
+
+type CardPayload struct {
+	Play game.Play `json:"play"`
+}
+
+type TrickResultPayload struct {
+	Number int        `json:"number"`
+	Trick  game.Trick `json:"trick"`
+	Cangou bool       `json:"cangou"`
+}
+
+type HandResultPayload struct {
+	Round  int32  `json:"round"`
+	Winner int    `json:"winner"`
+	Points int    `json:"points"`
+	Score  [2]int `json:"score"`
+}
+
+type MatchResultPayload struct {
+	Winner int    `json:"winner"`
+	Score  [2]int `json:"score"`
+	// Forfeit é o jogador que abandonou a mesa quando a partida acabou por W.O.
+	Forfeit *uuid.UUID `json:"forfeit,omitempty"`
+}
+
+type ElevenDecisionPayload struct {
+	Player uuid.UUID `json:"player"`
+	Team   int       `json:"team"`
+	Play   bool      `json:"play"`
+	Value  int       `json:"value"`
+}
+
+// loadMatch retorna o placar em memória da sala, recuperando de games.result
+// quando a sala ainda não está carregada
+func (h apiHandler) loadMatch(room pgstore.Game) (*game.Match, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	gameRoom := h.room(room.ID.String())
+	if gameRoom.match != nil {
+		return gameRoom.match, nil
+	}
+
+	rules, err := game.RulesFor(game.Variant(room.Variant))
+	if err != nil {
+		return nil, err
+	}
+
+	match := game.NewMatch(rules)
+	if len(room.Result) > 0 {
+		if err := json.Unmarshal(room.Result, match); err != nil {
+			return nil, err
+		}
+	}
+	match.Round = int(room.Round)
+
+	gameRoom.match = match
+	return match, nil
+}
+
+// startHand embaralha com o commitment publicado para a rodada, distribui a
+// mão e retorna o início da mão que deve ser enviado para a sala
+func (h apiHandler) startHand(ctx context.Context, roomID uuid.UUID, stage string) (StartGamePayload, *game.Hand, error) {
+	room, err := h.q.GetRoom(ctx, roomID)
+	if err != nil {
+		return StartGamePayload{}, nil, err
+	}
+
+	match, err := h.loadMatch(room)
+	if err != nil {
+		return StartGamePayload{}, nil, err
+	}
+	if match.Finished {
+		return StartGamePayload{}, nil, game.ErrMatchOver
+	}
+
+	seats, err := h.q.GetRoomSeats(ctx, roomID)
+	if err != nil {
+		return StartGamePayload{}, nil, err
+	}
+	if len(seats) != game.Capacity(int(room.TeamSize)) {
+		return StartGamePayload{}, nil, game.ErrRoomNotFull
+	}
+
+	// com a mesa cheia a posição na lista é a cadeira, e os times se alternam
+	players := make([]uuid.UUID, len(seats))
+	for i, seat := range seats {
+		players[i] = seat.ID
+	}
+
+	commitment, err := h.commitShuffle(ctx, room, players)
+	if err != nil {
+		return StartGamePayload{}, nil, err
+	}
+
+	hand, err := h.dealHand(ctx, room, players)
+	if err != nil {
+		return StartGamePayload{}, nil, err
+	}
+
+	h.mu.Lock()
+	payload := StartGamePayload{
+		Stage:      stage,
+		Commitment: commitment,
+		Round:      room.Round,
+		Vira:       hand.Vira,
+		Score:      match.Score,
+		Special:    hand.Special,
+	}
+	h.mu.Unlock()
+
+	return payload, hand, nil
+}
+
+// announceHand envia o início da mão para a sala e as cartas para cada jogador
+func (h apiHandler) announceHand(roomID uuid.UUID, start StartGamePayload, hand *game.Hand) {
+	h.notifyClients(roomID, StartGame, start)
+	h.sendHands(roomID, hand)
+	h.resetClock(roomID)
+}
+
+// dealHand compra as cartas da mão no baralho da sala, distribui seguindo
+// players.ordem e persiste a mão de cada jogador e a vira
+func (h apiHandler) dealHand(ctx context.Context, room pgstore.Game, players []uuid.UUID) (*game.Hand, error) {
+	rules, err := game.RulesFor(game.Variant(room.Variant))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(players) < 2 {
+		return nil, game.ErrNotEnoughPlayers
+	}
+
+	cards, err := h.decks.DrawCards(ctx, room.DeckID, game.CardsToDeal(rules, len(players)))
+	if err != nil {
+		return nil, err
+	}
+
+	hand, err := game.Deal(rules, players, int(room.Round-1), cards)
+	if err != nil {
+		return nil, err
+	}
+
+	// CreatePlayerHand sobrescreve a mão já salva, então uma distribuição que
+	// falhou no meio pode ser refeita
+	for _, player := range players {
+		playerCards, err := json.Marshal(hand.Cards[player])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.q.CreatePlayerHand(ctx, pgstore.CreatePlayerHandParams{
+			RoomID:   room.ID,
+			PlayerID: player,
+			Round:    room.Round,
+			Cards:    playerCards,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	vira, err := json.Marshal(hand.Vira)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.q.SetGameVira(ctx, pgstore.SetGameViraParams{Vira: vira, ID: room.ID}); err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	gameRoom := h.room(room.ID.String())
+	if gameRoom.match != nil {
+		gameRoom.match.Setup(hand)
+	}
+	gameRoom.hand = hand
+	gameRoom.round = room.Round
+	gameRoom.turnTimeout = time.Duration(room.TurnSeconds) * time.Second
+	h.mu.Unlock()
+
+	return hand, nil
+}
+
+// sendHands envia para cada jogador somente as próprias cartas. Na mão de onze
+// o time que decide também recebe as cartas do parceiro e na mão de ferro
+// ninguém recebe as cartas, só a quantidade
+func (h apiHandler) sendHands(roomID uuid.UUID, hand *game.Hand) {
+	h.mu.Lock()
+	round := h.room(roomID.String()).round
+	payloads := make(map[uuid.UUID]DealPayload, len(hand.Players))
+	for seat, player := range hand.Players {
+		payload := DealPayload{
+			Round:     round,
+			Cards:     hand.Cards[player],
+			CardCount: len(hand.Cards[player]),
+			Vira:      hand.Vira,
+			Special:   hand.Special,
+		}
+
+		if hand.Blind {
+			payload.Cards = nil
+		}
+
+		if hand.Special == game.MaoDeOnze && game.Team(seat) == hand.ElevenTeam {
+			payload.PartnersCards = make(map[uuid.UUID][]deck.Card)
+			for _, partner := range hand.Partners(player) {
+				payload.PartnersCards[partner] = hand.Cards[partner]
+			}
+		}
+
+		payloads[player] = payload
+	}
+	h.mu.Unlock()
+
+	for _, player := range hand.Players {
+		h.notifyPlayer(roomID, player, Deal, payloads[player])
+	}
+}
+
+// sendError responde à conexão com o erro da jogada
+func (h apiHandler) sendError(c *websocket.Conn, roomID uuid.UUID, err error) {
+	h.sendErrorRef(c, roomID, err, nil)
+}
+
+// sendErrorRef responde com o erro indicando o tipo da mensagem recusada
+func (h apiHandler) sendErrorRef(c *websocket.Conn, roomID uuid.UUID, err error, ref *EventType) {
+	// sem conexão a ação foi feita pelo relógio da jogada
+	if c == nil {
+		slog.Error("automatic move failed", "error", err)
+		return
+	}
+
+	payload := ErrorPayload{
+		Code:    "internal_error",
+		Message: err.Error(),
+		Ref:     ref,
+	}
+
+	var gameErr *game.Error
+	if errors.As(err, &gameErr) {
+		payload.Code = gameErr.Code
+	}
+
+	h.notifyConn(c, roomID, Error, payload)
+}
+
+// handlePlayCard valida e joga a carta na vaza atual, avisando a sala da carta
+// jogada, do resultado da vaza e do fim da mão
+func (h apiHandler) handlePlayCard(ctx context.Context, c *websocket.Conn, playerID, roomID uuid.UUID, body CardEvent) {
+	h.mu.Lock()
+	room := h.room(roomID.String())
+	hand := room.hand
+	if hand == nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, errNoHandInProgress)
+		return
+	}
+
+	code := body.Card
+	if body.Index != nil {
+		var err error
+		if code, err = hand.CardAt(playerID, *body.Index); err != nil {
+			h.mu.Unlock()
+			h.sendError(c, roomID, err)
+			return
+		}
+	}
+
+	play, err := hand.Play(playerID, code)
+	if err != nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	round := room.round
+	trick := hand.CurrentTrick().Clone()
+	trickNumber := len(hand.Tricks)
+	cards := hand.Cards[playerID]
+	handDone := hand.Done
+	h.mu.Unlock()
+
+	playerCards, err := json.Marshal(cards)
+	if err == nil {
+		err = h.q.UpdatePlayerHand(ctx, pgstore.UpdatePlayerHandParams{
+			Cards:    playerCards,
+			PlayerID: playerID,
+			Round:    round,
+		})
+	}
+	if err != nil {
+		slog.Error("failed to persist hand", "error", err)
+	}
+
+	h.notifyFrom(roomID, playerID, Card, CardPayload{Play: play})
+
+	if !trick.Done {
+		h.resetClock(roomID)
+		return
+	}
+
+	h.notifyClients(roomID, TrickResult, TrickResultPayload{
+		Number: trickNumber,
+		Trick:  trick,
+		Cangou: trick.Cangou(),
+	})
+
+	if handDone {
+		h.finishHand(ctx, roomID)
+		return
+	}
+	h.resetClock(roomID)
+}
+
+// handleElevenDecision recebe a decisão do time na mão de onze
+func (h apiHandler) handleElevenDecision(ctx context.Context, c *websocket.Conn, playerID, roomID uuid.UUID, body ElevenDecisionEvent) {
+	h.mu.Lock()
+	hand := h.room(roomID.String()).hand
+	if hand == nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, errNoHandInProgress)
+		return
+	}
+
+	if err := hand.Decide(playerID, *body.Play); err != nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	decision := ElevenDecisionPayload{
+		Player: playerID,
+		Team:   hand.ElevenTeam,
+		Play:   *body.Play,
+		Value:  hand.Value(),
+	}
+	handDone := hand.Done
+	h.mu.Unlock()
+
+	h.notifyFrom(roomID, playerID, ElevenDecision, decision)
+
+	if handDone {
+		h.finishHand(ctx, roomID)
+		return
+	}
+	h.resetClock(roomID)
+}
+
+// finishHand soma os pontos da mão ao placar, avisa a sala, volta o estado da
+// aposta para normal e revela a seed do embaralhamento. Se ninguém chegou aos
+// pontos da partida publica o commitment da próxima mão e espera as seeds
+func (h apiHandler) finishHand(ctx context.Context, roomID uuid.UUID) {
+	h.mu.Lock()
+	room := h.room(roomID.String())
+	stopClock(room)
+	hand, match := room.hand, room.match
+	if hand == nil || match == nil || match.Finished {
+		// a partida acabou por W.O. enquanto a jogada era processada
+		h.mu.Unlock()
+		return
+	}
+	round := room.round
+	points := 0
+	if hand.Winner != game.Tie {
+		points = hand.Value()
+	}
+	match.Apply(hand)
+	score := *match
+	h.mu.Unlock()
+
+	h.notifyClients(roomID, HandResult, HandResultPayload{
+		Round:  round,
+		Winner: hand.Winner,
+		Points: points,
+		Score:  score.Score,
+	})
+
+	if err := h.q.SetRoomState(ctx, pgstore.SetRoomStateParams{State: pgstore.StateNormal, ID: roomID}); err != nil {
+		slog.Error("failed to reset room state", "error", err)
+	}
+
+	h.saveScore(ctx, roomID, score)
+
+	// a mão terminou: revela a seed para que possa ser verificada
+	if err := h.revealShuffle(ctx, roomID, round); err != nil {
+		slog.Error("failed to reveal shuffle", "error", err)
+	}
+
+	if score.Finished {
+		h.notifyClients(roomID, MatchResult, MatchResultPayload{
+			Winner: score.Winner,
+			Score:  score.Score,
+		})
+		h.finishRoom(ctx, roomID)
+		return
+	}
+
+	// o commitment da próxima mão sai antes das seeds dos jogadores
+	next := int32(score.Round)
+	if _, err := h.prepareShuffle(ctx, roomID, next); err != nil {
+		slog.Error("failed to commit next shuffle", "error", err)
+	}
+	h.waitSeeds(roomID, next, hand.Players)
+}
+
+// forfeitMatch encerra a partida em andamento quando um jogador sentado não
+// volta a tempo: o time dele perde, a seed da mão é revelada e a sala é
+// arquivada
+func (h apiHandler) forfeitMatch(ctx context.Context, room pgstore.Game, playerID uuid.UUID, team int) {
+	match, err := h.loadMatch(room)
+	if err != nil {
+		slog.Error("failed to load match", "error", err)
+		return
+	}
+
+	h.mu.Lock()
+	gameRoom := h.room(room.ID.String())
+	stopClock(gameRoom)
+	stopShuffle(gameRoom)
+	gameRoom.shuffle = nil
+	gameRoom.hand = nil
+	match.Forfeit(team)
+	score := *match
+	h.mu.Unlock()
+
+	h.saveScore(ctx, room.ID, score)
+	if err := h.revealShuffle(ctx, room.ID, room.Round); err != nil {
+		slog.Error("failed to reveal shuffle", "error", err)
+	}
+
+	h.notifyClients(room.ID, MatchResult, MatchResultPayload{
+		Winner:  score.Winner,
+		Score:   score.Score,
+		Forfeit: &playerID,
+	})
+	h.finishRoom(ctx, room.ID)
+}
+
+// saveScore persiste o placar da partida em games.result
+func (h apiHandler) saveScore(ctx context.Context, roomID uuid.UUID, score game.Match) {
+	result, err := json.Marshal(score)
+	if err == nil {
+		err = h.q.SetGameScore(ctx, pgstore.SetGameScoreParams{
+			Round:  int32(score.Round),
+			Result: result,
+			ID:     roomID,
+		})
+	}
+	if err != nil {
+		slog.Error("failed to persist score", "error", err)
+	}
+}
@@ -5,38 +5,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
 
-	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
 	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/jwtauth/v5"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type EventType uint8
-
-const (
-	Message int = iota
-	StartGame
-	Card
-	Rise
-	Response
-)
-
-type Event struct {
-	Type    EventType `json:"type"`
-	Message []byte    `json:"message"`
-}
-
-type CardEvent struct {
-	Card string `json:"card"`
-}
-
 func (h apiHandler) handleEcho(w http.ResponseWriter, r *http.Request) {
 	message := chi.URLParam(r, "message")
 	fmt.Println(r.URL)
@@ -70,7 +53,42 @@ func returnData(result []byte, w http.ResponseWriter) {
 
 func (h apiHandler) handleCreateGame(w http.ResponseWriter, r *http.Request) {
 
-	deck, err := deck.CreateDeck()
+	type requestBody struct {
+		Variant game.Variant `json:"variant"`
+		Mode    string       `json:"mode"`
+		// TurnSeconds é o tempo de cada jogada, zero desliga o relógio
+		TurnSeconds *int `json:"turn_seconds"`
+	}
+
+	var body requestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	rules, err := game.RulesFor(body.Variant)
+	if err != nil {
+		http.Error(w, "invalid variant", http.StatusBadRequest)
+		return
+	}
+
+	teamSize, err := game.ParseMode(body.Mode)
+	if err != nil {
+		http.Error(w, "invalid mode", http.StatusBadRequest)
+		return
+	}
+
+	turnSeconds := h.cfg.TurnSeconds
+	if body.TurnSeconds != nil {
+		turnSeconds = *body.TurnSeconds
+	}
+	if turnSeconds < 0 {
+		http.Error(w, "invalid turn_seconds", http.StatusBadRequest)
+		return
+	}
+
+	deck, err := h.decks.CreateDeck(r.Context(), rules.Cards(), 0)
 
 	if err != nil {
 		slog.Error("CreateGame", "error", err)
@@ -78,7 +96,13 @@ func (h apiHandler) handleCreateGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	game, err := h.q.CreateNewGame(r.Context(), deck.DeckID)
+	room, err := h.q.CreateNewGame(r.Context(), pgstore.CreateNewGameParams{
+		DeckID:      deck.DeckID,
+		Seed:        deck.Seed,
+		Variant:     pgstore.Variant(rules.Variant),
+		TeamSize:    int32(teamSize),
+		TurnSeconds: int32(turnSeconds),
+	})
 	if err != nil {
 		slog.Error("CreateGame", "error", err)
 		returnError(w, http.StatusInternalServerError)
@@ -86,20 +110,26 @@ func (h apiHandler) handleCreateGame(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type responseBody struct {
-		ID        string `json:"id"`
-		CreatedAt string `json:"created_at"`
-		Result    []byte `json:"result"`
-		State     string `json:"state"`
-		Round     int32  `json:"round"`
+		ID          string `json:"id"`
+		CreatedAt   string `json:"created_at"`
+		Result      []byte `json:"result"`
+		State       string `json:"state"`
+		Round       int32  `json:"round"`
+		Variant     string `json:"variant"`
+		Mode        string `json:"mode"`
+		TurnSeconds int32  `json:"turn_seconds"`
 	}
 
 	result, err := json.Marshal(
 		responseBody{
-			ID:        game.ID.String(),
-			CreatedAt: game.CreatedAt.Time.String(),
-			Result:    game.Result,
-			State:     string(game.State),
-			Round:     game.Round,
+			ID:          room.ID.String(),
+			CreatedAt:   room.CreatedAt.Time.String(),
+			Result:      room.Result,
+			State:       string(room.State),
+			Round:       room.Round,
+			Variant:     string(room.Variant),
+			Mode:        game.Mode(int(room.TeamSize)),
+			TurnSeconds: room.TurnSeconds,
 		})
 	if err != nil {
 		returnError(w, http.StatusInternalServerError)
@@ -127,6 +157,8 @@ func (h apiHandler) handleEnterGame(w http.ResponseWriter, r *http.Request) {
 
 	type requestBody struct {
 		Name string `json:"name"`
+		// Team é opcional, sem time o jogador vai para o time com menos jogadores
+		Team *int `json:"team"`
 	}
 
 	var body requestBody
@@ -140,6 +172,38 @@ func (h apiHandler) handleEnterGame(w http.ResponseWriter, r *http.Request) {
 
 	//TODO:validate auth if necessary
 
+	room, err := h.q.GetRoom(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusNotFound)
+		return
+	}
+	if !inLobby(room) {
+		http.Error(w, errGameStarted.Error(), http.StatusConflict)
+		return
+	}
+
+	seats, err := h.q.GetRoomSeats(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	taken := make([]int, len(seats))
+	for i, seat := range seats {
+		taken[i] = int(seat.Ordem)
+	}
+
+	// confere o time e a lotação antes de criar o jogador; a cadeira é
+	// escolhida de novo em takeSeat
+	if _, _, err := game.AssignSeat(int(room.TeamSize), taken, body.Team); err != nil {
+		if errors.Is(err, game.ErrUnknownTeam) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
 	playerID, err := h.q.CreatePlayer(r.Context(), pgstore.CreatePlayerParams{
 		Name:   body.Name,
 		RoomID: roomID,
@@ -151,23 +215,32 @@ func (h apiHandler) handleEnterGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.q.GetRoom(r.Context(), roomID)
-
+	seat, team, err := h.takeSeat(r.Context(), room, playerID, body.Team)
 	if err != nil {
+		slog.Info("unable to seat player", "error", err)
+		if _, err := h.q.RemovePlayerFromRoom(r.Context(), playerID); err != nil {
+			slog.Error("failed to remove player", "error", err)
+		}
+		var gameErr *game.Error
+		if errors.As(err, &gameErr) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		returnError(w, http.StatusInternalServerError)
 		return
 	}
+	order := int32(seat)
 
-	playersInRoom, err := h.q.GetRoomPlayers(r.Context(), roomID)
-	if err != nil {
-		returnError(w, http.StatusInternalServerError)
-		return
+	// o primeiro jogador a entrar vira o host da sala
+	if err := h.q.SetGameHost(r.Context(), pgstore.SetGameHostParams{
+		ID:     roomID,
+		HostID: pgtype.UUID{Bytes: playerID, Valid: true},
+	}); err != nil {
+		slog.Error("failed to set room host", "error", err)
 	}
-	order := int32(len(playersInRoom))
 
-	if err := h.q.SetOrder(r.Context(), pgstore.SetOrderParams{Ordem: order, ID: playerID}); err != nil {
-		returnError(w, http.StatusInternalServerError)
-		return
+	if _, err := h.refreshLobby(r.Context(), roomID); err != nil {
+		slog.Error("failed to update lobby", "error", err)
 	}
 
 	var responsePayload = map[string]interface{}{
@@ -184,9 +257,10 @@ func (h apiHandler) handleEnterGame(w http.ResponseWriter, r *http.Request) {
 	type responseBody struct {
 		Token string `json:"token"`
 		Order int32  `json:"order"`
+		Team  int    `json:"team"`
 	}
 
-	result, err := json.Marshal(responseBody{Token: tokenString, Order: order})
+	result, err := json.Marshal(responseBody{Token: tokenString, Order: order, Team: team})
 
 	if err != nil {
 		returnError(w, http.StatusInternalServerError)
@@ -251,6 +325,23 @@ func (h apiHandler) handleConnectToRoom(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// last_seq é o último evento recebido; os seguintes são reenviados antes dos eventos ao vivo
+	var lastSeq *int64
+	if raw := r.URL.Query().Get("last_seq"); raw != "" {
+		seq, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || seq < 0 {
+			http.Error(w, "invalid last_seq", http.StatusBadRequest)
+			return
+		}
+		lastSeq = &seq
+	}
+
+	team, err := h.playerTeam(r.Context(), roomID, playerID)
+	if err != nil {
+		http.Error(w, "something went wrong", http.StatusInternalServerError)
+		return
+	}
+
 	c, err := h.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Warn("failed to upgrade connection", "error", err)
@@ -264,22 +355,27 @@ func (h apiHandler) handleConnectToRoom(w http.ResponseWriter, r *http.Request)
 	// Trava o mutex para fazer alteração no map de conexões
 	h.mu.Lock()
 
-	room, ok := h.clients[roomID.String()]
-
-	if !ok {
-		room = Room{
-			connections: make(map[*websocket.Conn]context.CancelFunc),
-		}
-	}
-
-	room.connections[c] = cancel
+	room := h.room(roomID.String())
+	cl := newClient(c, playerID, team, cancel)
+	cl.keepAlive(h.cfg.MaxMessageSize, h.cfg.PingInterval, h.cfg.PongTimeout)
+	room.connections[c] = cl
+	reconnected := h.resumeClient(cl, room, roomID, lastSeq)
 
 	slog.Info("new client", "room", roomID.String())
-	h.clients[roomID.String()] = room
 
 	h.mu.Unlock()
 
-	go h.readAndNotifyClients(c, r, playerID, roomID)
+	if reconnected {
+		h.notifyClients(roomID, PlayerReconnected, PresencePayload{Player: playerID})
+	}
+	h.sendClock(c, roomID)
+	h.sendChatHistory(ctx, c, roomID, team)
+
+	go cl.writePump(ctx, h.cfg.PingInterval)
+	go func() {
+		h.readAndNotifyClients(cl, r, playerID, roomID)
+		cancel()
+	}()
 
 	<-ctx.Done()
 }
@@ -296,6 +392,20 @@ func playerIsInRoom(players []uuid.UUID, playerID uuid.UUID) bool {
 	return false
 }
 
+// playerTeam retorna o time do jogador na mesa, ou noTeam se ele não tem cadeira
+func (h apiHandler) playerTeam(ctx context.Context, roomID, playerID uuid.UUID) (int, error) {
+	seats, err := h.q.GetRoomSeats(ctx, roomID)
+	if err != nil {
+		return noTeam, err
+	}
+	for _, seat := range seats {
+		if seat.ID == playerID {
+			return int(seat.Team), nil
+		}
+	}
+	return noTeam, nil
+}
+
 func (h apiHandler) handleStartGame(w http.ResponseWriter, r *http.Request) {
 	rawRoomId := chi.URLParam(r, "game_id")
 	roomID, err := uuid.Parse(rawRoomId)
@@ -329,50 +439,95 @@ func (h apiHandler) handleStartGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	type response struct {
-		Type  int    `json:"type"`
-		Event string `json:"event"`
-	}
-
-	payload := response{
-		Event: "start game",
-		Type:  StartGame,
+	if status, err := h.beginMatch(r.Context(), room, playerID); err != nil {
+		if status == http.StatusInternalServerError {
+			slog.Error("StartGame", "error", err)
+			returnError(w, status)
+			return
+		}
+		http.Error(w, err.Error(), status)
+		return
 	}
 
-	byteMessage, err := json.Marshal(payload)
+	start, hand, err := h.startHand(r.Context(), roomID, "start game")
 	if err != nil {
-		returnError(w, http.StatusUnauthorized)
+		slog.Error("StartGame", "error", err)
+		// a partida não começou, a sala volta para o lobby
+		if _, err := h.q.TransitionGameStatus(r.Context(), pgstore.TransitionGameStatusParams{
+			Next:    pgstore.LobbyStatusReadyCheck,
+			ID:      roomID,
+			Current: pgstore.LobbyStatusInProgress,
+		}); err != nil {
+			slog.Error("StartGame", "error", err)
+		}
+		if errors.Is(err, game.ErrRoomNotFull) || errors.Is(err, game.ErrMatchOver) {
+			returnError(w, http.StatusConflict)
+			return
+		}
+		returnError(w, http.StatusInternalServerError)
 		return
 	}
 
-	go h.notifyClients(byteMessage, roomID.String())
+	go h.announceHand(roomID, start, hand)
 
+	byteMessage, err := json.Marshal(start)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
 	returnData(byteMessage, w)
 	fmt.Println(playerID, room)
 
 }
 
-func (h apiHandler) readAndNotifyClients(c *websocket.Conn, r *http.Request, playerID uuid.UUID, roomID uuid.UUID) error {
+func (h apiHandler) readAndNotifyClients(cl *client, r *http.Request, playerID uuid.UUID, roomID uuid.UUID) error {
+	c := cl.conn
 	for {
 		msgType, msg, err := c.ReadMessage()
 
 		if err != nil || msgType == -1 {
-			h.mu.Lock()
-			h.disconectClient(c, r, playerID)
-			h.mu.Unlock()
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				slog.Info("connection lost", "player", playerID, "error", err)
+			}
+			h.disconectClient(c, playerID, roomID)
 			return err
 		}
+		// qualquer mensagem mostra que o cliente está vivo, não só o pong
+		cl.extendDeadline()
 
-		fmt.Println(roomID)
-
-		/*go func () {
-			for connection := range h.clients[roomID.String()].connections {
-				connection.WriteMessage(msgType, msg)
+		envelope, payload, err := decodeEnvelope(msg, roomID)
+		if err != nil {
+			var ref *EventType
+			if !errors.Is(err, errMalformedMessage) {
+				ref = &envelope.Type
 			}
-		}()*/
+			h.sendErrorRef(c, roomID, err, ref)
+			continue
+		}
 
-		if strings.Contains(string(msg), "echo:") {
-			c.WriteMessage(msgType, msg)
+		switch body := payload.(type) {
+		case *MessageEvent:
+			h.handleChatMessage(r.Context(), c, playerID, roomID, *body)
+		case *ReactionEvent:
+			h.handleReaction(c, playerID, roomID, *body)
+		case *CardEvent:
+			h.handlePlayCard(r.Context(), c, playerID, roomID, *body)
+		case *ResponseEvent:
+			if envelope.Type == EnvidoResponse {
+				h.handleEnvidoResponse(r.Context(), c, playerID, roomID, *body)
+			} else {
+				h.handleRaiseResponse(r.Context(), c, playerID, roomID, *body)
+			}
+		case *ElevenDecisionEvent:
+			h.handleElevenDecision(r.Context(), c, playerID, roomID, *body)
+		case *SeedEvent:
+			h.handleClientSeed(c, playerID, roomID, *body)
+		case *CallEvent:
+			if envelope.Type == Rise {
+				h.handleRise(c, playerID, roomID)
+			} else {
+				h.handleEnvido(r.Context(), c, playerID, roomID, envelope.Type)
+			}
 		}
 	}
 }
@@ -428,12 +583,13 @@ func (h apiHandler) getAllRooms(w http.ResponseWriter, r *http.Request) {
 	}
 
 	type roomsResponse struct {
-		ID string `json:"id"`
+		ID     string `json:"id"`
+		Status string `json:"status"`
 	}
 
 	var response []roomsResponse
 	for _, room := range rooms {
-		response = append(response, roomsResponse{ID: room.ID.String()})
+		response = append(response, roomsResponse{ID: room.ID.String(), Status: string(room.Status)})
 	}
 	result, err := json.Marshal(response)
 	if err != nil {
@@ -442,36 +598,3 @@ func (h apiHandler) getAllRooms(w http.ResponseWriter, r *http.Request) {
 	}
 	returnData(result, w)
 }
-
-func (h apiHandler) disconectClient(c *websocket.Conn, r *http.Request, playerId uuid.UUID) error {
-
-	slog.Info("disconect client")
-	room_id, err := h.q.RemovePlayerFromRoom(r.Context(), playerId)
-
-	if err != nil {
-		return err
-	}
-
-	delete(h.clients[room_id.String()].connections, c)
-	if len(h.clients[room_id.String()].connections) == 0 {
-		delete(h.clients, room_id.String())
-	}
-	room, err := h.q.GetRoomPlayers(r.Context(), room_id)
-	if err != nil {
-		slog.Error("erro ao terminar jogo", "error", err)
-		return err
-	}
-
-	if len(room) == 0 {
-		id, err := h.q.DeleteGameRoom(r.Context(), room_id)
-		if err != nil {
-			slog.Error("erro ao terminar jogo", "error", err, "id", id)
-			return err
-		}
-	}
-	return c.Close()
-}
-
-func (h apiHandler) getGameState(w http.ResponseWriter, r *http.Request) {
-
-}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type lobbyPlayer struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Seat  int32     `json:"seat"`
+	Team  int32     `json:"team"`
+	Ready bool      `json:"ready"`
+}
+
+// LobbyPayload é a situação da sala antes da partida começar. A sala fica em
+// waiting enquanto há cadeiras livres, ready_check com a mesa cheia,
+// in_progress depois que o host começa e finished quando a partida acaba ou
+// todos saem; salas finished ficam arquivadas no banco
+type LobbyPayload struct {
+	Status  pgstore.LobbyStatus `json:"status"`
+	Host    *uuid.UUID          `json:"host,omitempty"`
+	Players []lobbyPlayer       `json:"players"`
+}
+
+var (
+	errGameStarted = &game.Error{Code: "game_started", Message: "game already started"}
+	errNotAllReady = &game.Error{Code: "not_ready", Message: "every player must be ready to start"}
+	errNotHost     = &game.Error{Code: "not_host", Message: "only the host can start the game"}
+)
+
+// inLobby diz se a sala ainda aceita jogadores e mudanças de pronto
+func inLobby(room pgstore.Game) bool {
+	if room.ArchivedAt.Valid {
+		return false
+	}
+	return room.Status == pgstore.LobbyStatusWaiting || room.Status == pgstore.LobbyStatusReadyCheck
+}
+
+// takeSeat senta o jogador na cadeira livre que AssignSeat escolher. Se outro
+// jogador ocupou a cadeira no meio tempo, o índice único (room_id, ordem)
+// recusa e a escolha é refeita com as cadeiras atualizadas
+func (h apiHandler) takeSeat(ctx context.Context, room pgstore.Game, playerID uuid.UUID, team *int) (int, int, error) {
+	capacity := game.Capacity(int(room.TeamSize))
+	for attempt := 0; ; attempt++ {
+		seats, err := h.q.GetRoomSeats(ctx, room.ID)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		taken := make([]int, len(seats))
+		for i, seat := range seats {
+			taken[i] = int(seat.Ordem)
+		}
+
+		seat, chosen, err := game.AssignSeat(int(room.TeamSize), taken, team)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		err = h.q.SetSeat(ctx, pgstore.SetSeatParams{Ordem: int32(seat), Team: int32(chosen), ID: playerID})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && attempt < capacity {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		return seat, chosen, nil
+	}
+}
+
+// refreshLobby acerta o status da sala com a ocupação das cadeiras e avisa a
+// sala. Fora do lobby só retorna o estado atual
+func (h apiHandler) refreshLobby(ctx context.Context, roomID uuid.UUID) (LobbyPayload, error) {
+	room, err := h.q.GetRoom(ctx, roomID)
+	if err != nil {
+		return LobbyPayload{}, err
+	}
+
+	seats, err := h.q.GetRoomSeats(ctx, roomID)
+	if err != nil {
+		return LobbyPayload{}, err
+	}
+
+	lobby := LobbyPayload{Status: room.Status, Players: make([]lobbyPlayer, len(seats))}
+	if room.HostID.Valid {
+		host := uuid.UUID(room.HostID.Bytes)
+		lobby.Host = &host
+	}
+	for i, seat := range seats {
+		lobby.Players[i] = lobbyPlayer{ID: seat.ID, Name: seat.Name, Seat: seat.Ordem, Team: seat.Team, Ready: seat.Ready}
+	}
+
+	if !inLobby(room) {
+		return lobby, nil
+	}
+
+	next := pgstore.LobbyStatusWaiting
+	if len(seats) == game.Capacity(int(room.TeamSize)) {
+		next = pgstore.LobbyStatusReadyCheck
+	}
+	if next != room.Status {
+		changed, err := h.q.TransitionGameStatus(ctx, pgstore.TransitionGameStatusParams{
+			Next:    next,
+			ID:      roomID,
+			Current: room.Status,
+		})
+		if err != nil {
+			return LobbyPayload{}, err
+		}
+		if changed > 0 {
+			lobby.Status = next
+		}
+	}
+
+	h.notifyClients(roomID, Lobby, lobby)
+
+	// com a mesa cheia o commitment da primeira mão já sai, antes das seeds
+	if lobby.Status == pgstore.LobbyStatusReadyCheck {
+		if _, err := h.prepareShuffle(ctx, roomID, room.Round); err != nil {
+			return LobbyPayload{}, err
+		}
+	}
+	return lobby, nil
+}
+
+func allReady(seats []pgstore.GetRoomSeatsRow) bool {
+	for _, seat := range seats {
+		if !seat.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// handleReady marca o jogador como pronto. Sem ready no corpo alterna o valor
+// atual. client_seed é a seed do jogador para a primeira mão, aceita depois
+// que o commitment foi publicado
+func (h apiHandler) handleReady(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return
+	}
+
+	playerID, _, err := h.GetPlayerAndRoom(r, w, roomID)
+	if err != nil {
+		return
+	}
+
+	type requestBody struct {
+		Ready      *bool  `json:"ready"`
+		ClientSeed string `json:"client_seed"`
+	}
+
+	var body requestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	room, err := h.q.GetRoom(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusNotFound)
+		return
+	}
+	if !inLobby(room) {
+		http.Error(w, errGameStarted.Error(), http.StatusConflict)
+		return
+	}
+
+	seats, err := h.q.GetRoomSeats(r.Context(), roomID)
+	if err != nil {
+		slog.Error("Ready", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	seat := -1
+	for i := range seats {
+		if seats[i].ID == playerID {
+			seat = i
+		}
+	}
+	if seat < 0 {
+		returnError(w, http.StatusUnauthorized)
+		return
+	}
+
+	ready := !seats[seat].Ready
+	if body.Ready != nil {
+		ready = *body.Ready
+	}
+
+	if body.ClientSeed != "" {
+		if err := validateSeed(body.ClientSeed); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := h.addClientSeed(roomID, playerID, room.Round, body.ClientSeed); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.notifyFrom(roomID, playerID, Seed, SeedPayload{Round: room.Round})
+	}
+
+	if err := h.q.SetPlayerReady(r.Context(), pgstore.SetPlayerReadyParams{Ready: ready, ID: playerID}); err != nil {
+		slog.Error("Ready", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	lobby, err := h.refreshLobby(r.Context(), roomID)
+	if err != nil {
+		slog.Error("Ready", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	result, err := json.Marshal(lobby)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	returnData(result, w)
+}
+
+// beginMatch passa a sala de ready_check para in_progress. Só o host começa e
+// só com todas as cadeiras ocupadas por jogadores prontos
+func (h apiHandler) beginMatch(ctx context.Context, room pgstore.Game, playerID uuid.UUID) (int, error) {
+	if !isHost(room, playerID) {
+		return http.StatusForbidden, errNotHost
+	}
+	if room.ArchivedAt.Valid || room.Status == pgstore.LobbyStatusInProgress || room.Status == pgstore.LobbyStatusFinished {
+		return http.StatusConflict, errGameStarted
+	}
+
+	seats, err := h.q.GetRoomSeats(ctx, room.ID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if len(seats) != game.Capacity(int(room.TeamSize)) {
+		return http.StatusConflict, game.ErrRoomNotFull
+	}
+	if !allReady(seats) {
+		return http.StatusConflict, errNotAllReady
+	}
+
+	// a transição condicional impede dois inícios ao mesmo tempo
+	changed, err := h.q.TransitionGameStatus(ctx, pgstore.TransitionGameStatusParams{
+		Next:    pgstore.LobbyStatusInProgress,
+		ID:      room.ID,
+		Current: pgstore.LobbyStatusReadyCheck,
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if changed == 0 {
+		return http.StatusConflict, errGameStarted
+	}
+	return http.StatusOK, nil
+}
+
+// finishRoom encerra e arquiva a sala, que continua no banco para consulta
+func (h apiHandler) finishRoom(ctx context.Context, roomID uuid.UUID) {
+	if err := h.q.ArchiveGameRoom(ctx, roomID); err != nil {
+		slog.Error("failed to archive room", "error", err, "id", roomID)
+	}
+}
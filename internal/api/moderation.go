@@ -0,0 +1,297 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	DefaultChatRateLimit  = 5
+	DefaultChatRateWindow = 10 * time.Second
+	DefaultChatMute       = 30 * time.Second
+
+	maxReportReason = 255
+)
+
+// chatMute é um jogador calado no chat. until zero vale até o host liberar
+type chatMute struct {
+	until time.Time
+}
+
+// ChatMutePayload avisa a sala que o host calou ou liberou um jogador
+type ChatMutePayload struct {
+	Player uuid.UUID  `json:"player"`
+	Muted  bool       `json:"muted"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+var errMutedByHost = &game.Error{Code: "muted", Message: "the host muted you"}
+
+func errMuted(remaining time.Duration) error {
+	return &game.Error{Code: "muted", Message: fmt.Sprintf("you are muted for %ds", int(remaining.Seconds()+0.5))}
+}
+
+func errRateLimited(mute time.Duration) error {
+	return &game.Error{Code: "rate_limited", Message: fmt.Sprintf("too many messages, muted for %ds", int(mute.Seconds()))}
+}
+
+// allowChat confere se o jogador pode mandar uma mensagem agora. Quem passa de
+// ChatRateLimit mensagens em ChatRateWindow fica calado por ChatMute
+func (h apiHandler) allowChat(roomID, playerID uuid.UUID) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.room(roomID.String())
+	now := time.Now()
+
+	if err := room.checkMute(playerID, now); err != nil {
+		return err
+	}
+	if h.cfg.ChatRateLimit <= 0 {
+		return nil
+	}
+
+	if !withinRate(room.chatSent, playerID, h.cfg.ChatRateLimit, h.cfg.ChatRateWindow, now) {
+		delete(room.chatSent, playerID)
+		room.muted[playerID] = chatMute{until: now.Add(h.cfg.ChatMute)}
+		return errRateLimited(h.cfg.ChatMute)
+	}
+	return nil
+}
+
+// checkMute retorna o erro se o jogador está calado, apagando o silêncio que já
+// acabou. Deve ser chamado com h.mu travado
+func (room *Room) checkMute(playerID uuid.UUID, now time.Time) error {
+	mute, ok := room.muted[playerID]
+	if !ok {
+		return nil
+	}
+	if mute.until.IsZero() {
+		return errMutedByHost
+	}
+	if now.Before(mute.until) {
+		return errMuted(mute.until.Sub(now))
+	}
+	delete(room.muted, playerID)
+	return nil
+}
+
+// withinRate registra um envio do jogador se ele ainda não mandou limit
+// mensagens na última window
+func withinRate(sent map[uuid.UUID][]time.Time, playerID uuid.UUID, limit int, window time.Duration, now time.Time) bool {
+	times := sent[playerID]
+	for len(times) > 0 && now.Sub(times[0]) >= window {
+		times = times[1:]
+	}
+	if len(times) >= limit {
+		sent[playerID] = times
+		return false
+	}
+	sent[playerID] = append(times, now)
+	return true
+}
+
+// hostTarget confere que quem fez a requisição é o host da sala e retorna o
+// jogador alvo da ação
+func (h apiHandler) hostTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	playerID, _, err := h.GetPlayerAndRoom(r, w, roomID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	target, err := uuid.Parse(chi.URLParam(r, "player_id"))
+	if err != nil || target == playerID {
+		returnError(w, http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	room, err := h.q.GetRoom(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusNotFound)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !isHost(room, playerID) {
+		http.Error(w, "only the host can do this", http.StatusForbidden)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	players, err := h.q.GetRoomPlayers(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if !playerIsInRoom(players, target) {
+		returnError(w, http.StatusNotFound)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return roomID, target, true
+}
+
+func isHost(room pgstore.Game, playerID uuid.UUID) bool {
+	return room.HostID.Valid && uuid.UUID(room.HostID.Bytes) == playerID
+}
+
+// handleMutePlayer cala um jogador no chat. Sem seconds vale até o host liberar
+func (h apiHandler) handleMutePlayer(w http.ResponseWriter, r *http.Request) {
+	roomID, target, ok := h.hostTarget(w, r)
+	if !ok {
+		return
+	}
+
+	type requestBody struct {
+		Seconds int `json:"seconds"`
+	}
+
+	var body requestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if body.Seconds < 0 {
+		http.Error(w, "invalid seconds", http.StatusBadRequest)
+		return
+	}
+
+	var mute chatMute
+	payload := ChatMutePayload{Player: target, Muted: true}
+	if body.Seconds > 0 {
+		mute.until = time.Now().Add(time.Duration(body.Seconds) * time.Second)
+		payload.Until = &mute.until
+	}
+
+	h.mu.Lock()
+	h.room(roomID.String()).muted[target] = mute
+	h.mu.Unlock()
+
+	h.notifyClients(roomID, ChatMute, payload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUnmutePlayer libera um jogador calado
+func (h apiHandler) handleUnmutePlayer(w http.ResponseWriter, r *http.Request) {
+	roomID, target, ok := h.hostTarget(w, r)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	delete(h.room(roomID.String()).muted, target)
+	h.mu.Unlock()
+
+	h.notifyClients(roomID, ChatMute, ChatMutePayload{Player: target})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleReportMessage guarda a denúncia de uma mensagem para revisão. A
+// denúncia leva uma cópia do texto, então continua lá depois que a sala acaba
+func (h apiHandler) handleReportMessage(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return
+	}
+
+	playerID, _, err := h.GetPlayerAndRoom(r, w, roomID)
+	if err != nil {
+		return
+	}
+
+	messageID, err := uuid.Parse(chi.URLParam(r, "message_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return
+	}
+
+	type requestBody struct {
+		Reason string `json:"reason"`
+	}
+
+	var body requestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if utf8.RuneCountInString(body.Reason) > maxReportReason {
+		http.Error(w, fmt.Sprintf("reason must have at most %d characters", maxReportReason), http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.playerTeam(r.Context(), roomID, playerID)
+	if err != nil {
+		slog.Error("ReportMessage", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	message, err := h.q.GetMessage(r.Context(), messageID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !chatVisible(message, roomID, team)) {
+		returnError(w, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.Error("ReportMessage", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+	if !message.Player.Valid {
+		// o autor saiu da sala e não há mais quem denunciar
+		http.Error(w, "message author left the room", http.StatusGone)
+		return
+	}
+	if uuid.UUID(message.Player.Bytes) == playerID {
+		http.Error(w, "cannot report your own message", http.StatusBadRequest)
+		return
+	}
+
+	reportID, err := h.q.CreateChatReport(r.Context(), pgstore.CreateChatReportParams{
+		MessageID:      pgtype.UUID{Bytes: message.ID, Valid: true},
+		RoomID:         roomID,
+		Reporter:       playerID,
+		ReportedPlayer: uuid.UUID(message.Player.Bytes),
+		Message:        message.Message,
+		Reason:         body.Reason,
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		http.Error(w, "message already reported", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.Error("ReportMessage", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	result, err := json.Marshal(map[string]string{"id": reportID.String()})
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	returnData(result, w)
+}
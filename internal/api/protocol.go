@@ -0,0 +1,303 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/google/uuid"
+)
+
+// ProtocolVersion é a versão do envelope. Mensagens de outra versão são recusadas
+const ProtocolVersion = 1
+
+type EventType uint8
+
+const (
+	Message EventType = iota
+	StartGame
+	Card
+	Rise
+	Response
+	ShuffleReveal
+	Deal
+	TrickResult
+	HandResult
+	Error
+	ElevenDecision
+	MatchResult
+	Envido
+	RealEnvido
+	FaltaEnvido
+	Flor
+	EnvidoResponse
+	EnvidoResult
+	TurnClock
+	PlayerDisconnected
+	PlayerReconnected
+	PlayerLeft
+	Resync
+	ChatHistory
+	ChatMute
+	Reaction
+	Lobby
+	ShuffleCommit
+	Seed
+	ContraFlor
+	ContraFlorAlResto
+)
+
+var eventNames = map[EventType]string{
+	Message:            "message",
+	StartGame:          "start game",
+	Card:               "card",
+	Rise:               "rise",
+	Response:           "response",
+	ShuffleReveal:      "shuffle reveal",
+	Deal:               "deal",
+	TrickResult:        "trick result",
+	HandResult:         "hand result",
+	Error:              "error",
+	ElevenDecision:     "eleven decision",
+	MatchResult:        "match result",
+	Envido:             "envido",
+	RealEnvido:         "real envido",
+	FaltaEnvido:        "falta envido",
+	Flor:               "flor",
+	EnvidoResponse:     "envido response",
+	EnvidoResult:       "envido result",
+	TurnClock:          "turn clock",
+	PlayerDisconnected: "player disconnected",
+	PlayerReconnected:  "player reconnected",
+	PlayerLeft:         "player left",
+	Resync:             "resync",
+	ChatHistory:        "chat history",
+	ChatMute:           "chat mute",
+	Reaction:           "reaction",
+	Lobby:              "lobby",
+	ShuffleCommit:      "shuffle commit",
+	Seed:               "seed",
+	ContraFlor:         "contraflor",
+	ContraFlorAlResto:  "contraflor al resto",
+}
+
+func (t EventType) String() string {
+	if name, ok := eventNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("event(%d)", t)
+}
+
+// Envelope é o formato de toda mensagem do WebSocket, nos dois sentidos. Seq e
+// Sender são preenchidos pelo servidor; Sender fica vazio nos eventos do servidor
+type Envelope struct {
+	Type    EventType       `json:"type"`
+	Version int             `json:"version"`
+	Seq     int64           `json:"seq,omitempty"`
+	Room    uuid.UUID       `json:"room"`
+	Sender  *uuid.UUID      `json:"sender,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Payload é o conteúdo de uma mensagem enviada pelo cliente
+type Payload interface {
+	Validate() error
+}
+
+type CardEvent struct {
+	Card string `json:"card"`
+	// Index escolhe a carta pela posição, na mão de ferro o jogador não vê as cartas
+	Index *int `json:"index,omitempty"`
+}
+
+func (e *CardEvent) Validate() error {
+	if (e.Card == "") == (e.Index == nil) {
+		return fmt.Errorf("send either card or index")
+	}
+	return nil
+}
+
+// CallEvent é o pedido de truco ou de envido, sem conteúdo
+type CallEvent struct{}
+
+func (e *CallEvent) Validate() error {
+	return nil
+}
+
+type ResponseEvent struct {
+	Answer game.Answer `json:"answer"`
+}
+
+func (e *ResponseEvent) Validate() error {
+	switch e.Answer {
+	case game.Accept, game.Decline, game.ReRaise:
+		return nil
+	}
+	return game.ErrInvalidAnswer
+}
+
+type ElevenDecisionEvent struct {
+	Play *bool `json:"play"`
+}
+
+func (e *ElevenDecisionEvent) Validate() error {
+	if e.Play == nil {
+		return fmt.Errorf("play is required")
+	}
+	return nil
+}
+
+// SeedEvent é a seed do jogador para a mão round, enviada depois do commitment
+type SeedEvent struct {
+	Round int32  `json:"round"`
+	Seed  string `json:"seed"`
+}
+
+func (e *SeedEvent) Validate() error {
+	return validateSeed(e.Seed)
+}
+
+// validateSeed recusa seeds vazias, longas ou com ":", que separa as seeds na combinação
+func validateSeed(seed string) error {
+	if seed == "" || len(seed) > maxClientSeed {
+		return fmt.Errorf("seed must have 1 to %d bytes", maxClientSeed)
+	}
+	if strings.Contains(seed, ":") {
+		return fmt.Errorf("seed must not contain \":\"")
+	}
+	return nil
+}
+
+// inboundPayloads são os eventos que o cliente pode enviar e o payload de cada um
+var inboundPayloads = map[EventType]func() Payload{
+	Message:           func() Payload { return &MessageEvent{} },
+	Reaction:          func() Payload { return &ReactionEvent{} },
+	Card:              func() Payload { return &CardEvent{} },
+	Rise:              func() Payload { return &CallEvent{} },
+	Response:          func() Payload { return &ResponseEvent{} },
+	ElevenDecision:    func() Payload { return &ElevenDecisionEvent{} },
+	Envido:            func() Payload { return &CallEvent{} },
+	RealEnvido:        func() Payload { return &CallEvent{} },
+	FaltaEnvido:       func() Payload { return &CallEvent{} },
+	Flor:              func() Payload { return &CallEvent{} },
+	ContraFlor:        func() Payload { return &CallEvent{} },
+	ContraFlorAlResto: func() Payload { return &CallEvent{} },
+	EnvidoResponse:    func() Payload { return &ResponseEvent{} },
+	Seed:              func() Payload { return &SeedEvent{} },
+}
+
+// outboundPayloads é o payload de cada evento enviado pelo servidor
+var outboundPayloads = map[EventType]reflect.Type{
+	Message:            reflect.TypeFor[ChatPayload](),
+	StartGame:          reflect.TypeFor[StartGamePayload](),
+	Card:               reflect.TypeFor[CardPayload](),
+	Rise:               reflect.TypeFor[RaisePayload](),
+	Response:           reflect.TypeFor[RaisePayload](),
+	ShuffleReveal:      reflect.TypeFor[ShuffleRevealPayload](),
+	Deal:               reflect.TypeFor[DealPayload](),
+	TrickResult:        reflect.TypeFor[TrickResultPayload](),
+	HandResult:         reflect.TypeFor[HandResultPayload](),
+	Error:              reflect.TypeFor[ErrorPayload](),
+	ElevenDecision:     reflect.TypeFor[ElevenDecisionPayload](),
+	MatchResult:        reflect.TypeFor[MatchResultPayload](),
+	Envido:             reflect.TypeFor[EnvidoPayload](),
+	RealEnvido:         reflect.TypeFor[EnvidoPayload](),
+	FaltaEnvido:        reflect.TypeFor[EnvidoPayload](),
+	Flor:               reflect.TypeFor[EnvidoPayload](),
+	EnvidoResponse:     reflect.TypeFor[EnvidoPayload](),
+	EnvidoResult:       reflect.TypeFor[EnvidoResultPayload](),
+	TurnClock:          reflect.TypeFor[ClockPayload](),
+	PlayerDisconnected: reflect.TypeFor[PresencePayload](),
+	PlayerReconnected:  reflect.TypeFor[PresencePayload](),
+	PlayerLeft:         reflect.TypeFor[PresencePayload](),
+	Resync:             reflect.TypeFor[ResyncPayload](),
+	ChatHistory:        reflect.TypeFor[ChatHistoryPayload](),
+	ChatMute:           reflect.TypeFor[ChatMutePayload](),
+	Reaction:           reflect.TypeFor[ReactionPayload](),
+	Lobby:              reflect.TypeFor[LobbyPayload](),
+	ShuffleCommit:      reflect.TypeFor[ShuffleCommitPayload](),
+	Seed:               reflect.TypeFor[SeedPayload](),
+	ContraFlor:         reflect.TypeFor[EnvidoPayload](),
+	ContraFlorAlResto:  reflect.TypeFor[EnvidoPayload](),
+}
+
+// ErrorPayload é a resposta para uma mensagem recusada
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Ref é o tipo da mensagem recusada, quando foi possível ler
+	Ref *EventType `json:"ref,omitempty"`
+}
+
+// ResyncPayload avisa que os eventos perdidos já saíram do histórico e o
+// cliente precisa buscar o estado da mesa
+type ResyncPayload struct {
+	Seq int64 `json:"seq"`
+}
+
+var (
+	errMalformedMessage    = &game.Error{Code: "malformed_message", Message: "message is not a valid envelope"}
+	errUnsupportedVersion  = &game.Error{Code: "unsupported_version", Message: fmt.Sprintf("protocol version must be %d", ProtocolVersion)}
+	errUnknownEvent        = &game.Error{Code: "unknown_event", Message: "unknown event type"}
+	errWrongRoom           = &game.Error{Code: "wrong_room", Message: "message is for another room"}
+	errNoHandInProgress    = &game.Error{Code: "no_hand", Message: "no hand in progress"}
+	errUnregisteredPayload = fmt.Errorf("payload does not match the event type")
+)
+
+// newEnvelope monta a mensagem do servidor conferindo o payload com o registro
+func newEnvelope(t EventType, roomID uuid.UUID, payload any) (Envelope, error) {
+	if reflect.TypeOf(payload) != outboundPayloads[t] {
+		return Envelope{}, fmt.Errorf("%s: %w", t, errUnregisteredPayload)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{Type: t, Version: ProtocolVersion, Room: roomID, Payload: raw}, nil
+}
+
+// decodeEnvelope lê e valida uma mensagem do cliente. O Envelope retornado
+// serve para identificar a mensagem recusada mesmo quando há erro
+func decodeEnvelope(message []byte, roomID uuid.UUID) (Envelope, Payload, error) {
+	var envelope Envelope
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&envelope); err != nil {
+		return envelope, nil, errMalformedMessage
+	}
+
+	if envelope.Version != ProtocolVersion {
+		return envelope, nil, errUnsupportedVersion
+	}
+	if envelope.Room != uuid.Nil && envelope.Room != roomID {
+		return envelope, nil, errWrongRoom
+	}
+
+	newPayload, ok := inboundPayloads[envelope.Type]
+	if !ok {
+		return envelope, nil, errUnknownEvent
+	}
+
+	payload := newPayload()
+	if len(envelope.Payload) > 0 && !bytes.Equal(envelope.Payload, []byte("null")) {
+		decoder := json.NewDecoder(bytes.NewReader(envelope.Payload))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(payload); err != nil {
+			return envelope, nil, invalidPayload(err)
+		}
+	}
+	if err := payload.Validate(); err != nil {
+		return envelope, nil, invalidPayload(err)
+	}
+
+	return envelope, payload, nil
+}
+
+func invalidPayload(err error) error {
+	return &game.Error{Code: "invalid_payload", Message: err.Error()}
+}
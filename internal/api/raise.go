@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// stakeState é o estado salvo em games.state para o nível da aposta: o nome do
+// pedido na escada da variante que levou a mão até level
+func stakeState(rules game.Rules, level int) pgstore.State {
+	if level == 0 || level > len(rules.Calls) {
+		return pgstore.StateNormal
+	}
+	return pgstore.State(rules.Calls[level-1])
+}
+
+// RaisePayload é o pedido de truco (Rise) ou a resposta a ele (Response)
+type RaisePayload struct {
+	Player uuid.UUID  `json:"player"`
+	Team   int        `json:"team"`
+	Call   string     `json:"call,omitempty"`
+	Answer string     `json:"answer,omitempty"`
+	Value  int        `json:"value"`
+	Raise  game.Raise `json:"raise"`
+}
+
+func (h apiHandler) handleRise(c *websocket.Conn, playerID, roomID uuid.UUID) {
+	h.mu.Lock()
+	hand := h.room(roomID.String()).hand
+	if hand == nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, errNoHandInProgress)
+		return
+	}
+
+	call := hand.NextCall()
+	if err := hand.Call(playerID); err != nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	seat, _ := hand.Seat(playerID)
+	payload := RaisePayload{
+		Player: playerID,
+		Team:   game.Team(seat),
+		Call:   call,
+		Value:  hand.Rules.Ladder[hand.Raise.Level+1],
+		Raise:  hand.Raise,
+	}
+	h.mu.Unlock()
+
+	h.notifyFrom(roomID, playerID, Rise, payload)
+	h.resetClock(roomID)
+}
+
+func (h apiHandler) handleRaiseResponse(ctx context.Context, c *websocket.Conn, playerID, roomID uuid.UUID, body ResponseEvent) {
+	h.mu.Lock()
+	room := h.room(roomID.String())
+	hand := room.hand
+	if hand == nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, errNoHandInProgress)
+		return
+	}
+
+	if err := hand.Respond(playerID, body.Answer); err != nil {
+		h.mu.Unlock()
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	seat, _ := hand.Seat(playerID)
+	payload := RaisePayload{
+		Player: playerID,
+		Team:   game.Team(seat),
+		Answer: string(body.Answer),
+		Value:  hand.Value(),
+		Raise:  hand.Raise,
+	}
+	if body.Answer == game.ReRaise {
+		payload.Call = hand.NextCall()
+	}
+	state, handDone := stakeState(hand.Rules, hand.Raise.Level), hand.Done
+	h.mu.Unlock()
+
+	h.notifyFrom(roomID, playerID, Response, payload)
+
+	if handDone {
+		h.finishHand(ctx, roomID)
+		return
+	}
+
+	if err := h.q.SetRoomState(ctx, pgstore.SetRoomStateParams{State: state, ID: roomID}); err != nil {
+		slog.Error("failed to persist room state", "error", err)
+	}
+	h.resetClock(roomID)
+}
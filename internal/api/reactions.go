@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	DefaultReactionRateLimit  = 3
+	DefaultReactionRateWindow = 5 * time.Second
+)
+
+type ReactionKind string
+
+const (
+	ReactionPhrase ReactionKind = "phrase"
+	ReactionEmote  ReactionKind = "emote"
+)
+
+// QuickReaction é uma frase pronta ou um emote que o jogador manda com um toque
+type QuickReaction struct {
+	ID   string       `json:"id"`
+	Kind ReactionKind `json:"kind"`
+	Text string       `json:"text"`
+}
+
+// reactionCatalog são as reações aceitas pelo servidor, na ordem em que o
+// cliente mostra
+var reactionCatalog = []QuickReaction{
+	{ID: "truco", Kind: ReactionPhrase, Text: "Truco!"},
+	{ID: "seis", Kind: ReactionPhrase, Text: "Seis!"},
+	{ID: "vem", Kind: ReactionPhrase, Text: "Vem!"},
+	{ID: "cai_fora", Kind: ReactionPhrase, Text: "Cai fora"},
+	{ID: "desce", Kind: ReactionPhrase, Text: "Desce!"},
+	{ID: "boa", Kind: ReactionPhrase, Text: "Boa, parceiro!"},
+	{ID: "tenho_manilha", Kind: ReactionPhrase, Text: "Tenho manilha"},
+	{ID: "laugh", Kind: ReactionEmote, Text: "😂"},
+	{ID: "think", Kind: ReactionEmote, Text: "🤔"},
+	{ID: "fire", Kind: ReactionEmote, Text: "🔥"},
+	{ID: "clap", Kind: ReactionEmote, Text: "👏"},
+	{ID: "cry", Kind: ReactionEmote, Text: "😭"},
+	{ID: "angry", Kind: ReactionEmote, Text: "😠"},
+	{ID: "wink", Kind: ReactionEmote, Text: "😉"},
+}
+
+var reactions = make(map[string]QuickReaction, len(reactionCatalog))
+
+func init() {
+	for _, reaction := range reactionCatalog {
+		reactions[reaction.ID] = reaction
+	}
+}
+
+// ReactionEvent é uma reação do catálogo enviada pelo jogador
+type ReactionEvent struct {
+	ID string `json:"id"`
+}
+
+func (e *ReactionEvent) Validate() error {
+	if _, ok := reactions[e.ID]; !ok {
+		return fmt.Errorf("unknown reaction %q", e.ID)
+	}
+	return nil
+}
+
+// ReactionPayload é a reação repassada para a sala
+type ReactionPayload struct {
+	Player uuid.UUID `json:"player"`
+	ID     string    `json:"id"`
+}
+
+var errReactionRateLimited = &game.Error{Code: "rate_limited", Message: "too many reactions"}
+
+// handleReaction repassa a reação para a sala. Reações não vão para o banco nem
+// para o histórico de eventos, quem conecta depois não as recebe
+func (h apiHandler) handleReaction(c *websocket.Conn, playerID, roomID uuid.UUID, body ReactionEvent) {
+	if err := h.allowReaction(roomID, playerID); err != nil {
+		h.sendError(c, roomID, err)
+		return
+	}
+
+	h.notify(roomID, playerID, everyone, Reaction, ReactionPayload{Player: playerID, ID: body.ID}, false)
+}
+
+// allowReaction aplica o limite de reações. Quem está calado no chat também
+// não manda reações
+func (h apiHandler) allowReaction(roomID, playerID uuid.UUID) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.room(roomID.String())
+	now := time.Now()
+
+	if err := room.checkMute(playerID, now); err != nil {
+		return err
+	}
+	if h.cfg.ReactionRateLimit > 0 &&
+		!withinRate(room.reactionSent, playerID, h.cfg.ReactionRateLimit, h.cfg.ReactionRateWindow, now) {
+		return errReactionRateLimited
+	}
+	return nil
+}
+
+func (h apiHandler) getReactions(w http.ResponseWriter, r *http.Request) {
+	result, err := json.Marshal(reactionCatalog)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	returnData(result, w)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultReconnectGrace é quanto tempo a cadeira fica guardada depois que a
+// conexão do jogador cai
+const DefaultReconnectGrace = 30 * time.Second
+
+// awayPlayer é um jogador sem conexão aguardando o fim do tempo de reconexão
+type awayPlayer struct {
+	timer *time.Timer
+	// lastSeq é o último evento escrito na conexão antes da queda
+	lastSeq int64
+}
+
+func (room *Room) isConnected(player uuid.UUID) bool {
+	for _, client := range room.connections {
+		if client.playerID == player {
+			return true
+		}
+	}
+	return false
+}
+
+// PresencePayload avisa que um jogador caiu, voltou ou saiu da sala
+type PresencePayload struct {
+	Player uuid.UUID `json:"player"`
+	// Grace são os segundos que o jogador tem para voltar
+	Grace int `json:"grace,omitempty"`
+}
+
+// disconectClient tira a conexão da sala. Se era a última conexão do jogador a
+// cadeira fica guardada pelo tempo de reconexão antes de o jogador sair da sala
+func (h apiHandler) disconectClient(c *websocket.Conn, playerID, roomID uuid.UUID) error {
+	slog.Info("disconect client")
+
+	h.mu.Lock()
+	room, ok := h.clients[roomID.String()]
+	if !ok {
+		h.mu.Unlock()
+		return c.Close()
+	}
+	// eventos ainda na fila ou que falharam ao escrever são reenviados na volta
+	lastSeq := room.seq
+	if cl, ok := room.connections[c]; ok {
+		lastSeq = cl.written.Load()
+	}
+	delete(room.connections, c)
+
+	if room.isConnected(playerID) {
+		h.mu.Unlock()
+		return c.Close()
+	}
+
+	grace := h.cfg.ReconnectGrace
+	room.away[playerID] = &awayPlayer{
+		timer:   time.AfterFunc(grace, func() { h.removePlayer(roomID, playerID) }),
+		lastSeq: lastSeq,
+	}
+	h.mu.Unlock()
+
+	h.notifyClients(roomID, PlayerDisconnected, PresencePayload{Player: playerID, Grace: int(grace.Seconds())})
+	return c.Close()
+}
+
+// resumeClient devolve a cadeira ao jogador que voltou dentro do tempo de
+// reconexão e reenvia os eventos depois de lastSeq. Sem lastSeq, reenvia o que
+// o jogador perdeu desde a queda. Deve ser chamado com h.mu travado logo depois
+// de registrar a conexão; retorna se o jogador estava no tempo de reconexão
+func (h apiHandler) resumeClient(cl *client, room *Room, roomID uuid.UUID, lastSeq *int64) bool {
+	away, wasAway := room.away[cl.playerID]
+	if wasAway {
+		away.timer.Stop()
+		delete(room.away, cl.playerID)
+		if lastSeq == nil {
+			lastSeq = &away.lastSeq
+		}
+	}
+
+	// a conexão nova está em dia até onde o reenvio começa
+	cl.written.Store(room.seq)
+	if lastSeq != nil {
+		cl.written.Store(min(*lastSeq, room.seq))
+		if err := room.replay(cl, roomID, *lastSeq); err != nil {
+			slog.Error("failed to replay events", "error", err)
+		}
+	}
+	return wasAway
+}
+
+// removePlayer tira da sala o jogador que não voltou a tempo. Com a partida
+// em andamento o time dele perde por W.O. A sala é encerrada e arquivada
+// quando não sobra ninguém
+func (h apiHandler) removePlayer(roomID, playerID uuid.UUID) {
+	h.mu.Lock()
+	gameRoom, ok := h.clients[roomID.String()]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	if _, away := gameRoom.away[playerID]; !away {
+		// voltou antes do tempo acabar
+		h.mu.Unlock()
+		return
+	}
+	delete(gameRoom.away, playerID)
+	h.mu.Unlock()
+
+	// o tempo de reconexão não pertence a nenhuma requisição
+	ctx := context.Background()
+
+	room, err := h.q.GetRoom(ctx, roomID)
+	if err != nil {
+		slog.Error("failed to get room", "error", err)
+		return
+	}
+
+	if room.Status == pgstore.LobbyStatusInProgress && !room.ArchivedAt.Valid {
+		seats, err := h.q.GetRoomSeats(ctx, roomID)
+		if err != nil {
+			slog.Error("failed to get seats", "error", err)
+			return
+		}
+		// com a mesa cheia a posição na lista é a cadeira, como no startHand
+		for i, seat := range seats {
+			if seat.ID == playerID {
+				h.forfeitMatch(ctx, room, playerID, game.Team(i))
+				break
+			}
+		}
+	}
+
+	if inLobby(room) {
+		if _, err := h.q.RemovePlayerFromRoom(ctx, playerID); err != nil {
+			slog.Error("failed to remove player", "error", err)
+			return
+		}
+	} else {
+		// apagar o jogador tiraria o autor do chat e levaria junto, em cascata,
+		// as mãos da partida; fora do lobby ele só deixa a cadeira
+		if _, err := h.q.DetachPlayer(ctx, playerID); err != nil {
+			slog.Error("failed to detach player", "error", err)
+			return
+		}
+		if err := h.q.ClearGameHost(ctx, pgstore.ClearGameHostParams{
+			ID:     roomID,
+			HostID: pgtype.UUID{Bytes: playerID, Valid: true},
+		}); err != nil {
+			slog.Error("failed to clear room host", "error", err)
+		}
+	}
+
+	players, err := h.q.GetRoomPlayers(ctx, roomID)
+	if err != nil {
+		slog.Error("erro ao terminar jogo", "error", err)
+		return
+	}
+
+	if len(players) > 0 {
+		// se o host saiu, o próximo jogador da mesa assume
+		if err := h.q.SetGameHost(ctx, pgstore.SetGameHostParams{
+			ID:     roomID,
+			HostID: pgtype.UUID{Bytes: players[0], Valid: true},
+		}); err != nil {
+			slog.Error("failed to set room host", "error", err)
+		}
+		h.notifyClients(roomID, PlayerLeft, PresencePayload{Player: playerID})
+		// no lobby a cadeira livre volta a sala para waiting
+		if _, err := h.refreshLobby(ctx, roomID); err != nil {
+			slog.Error("failed to update lobby", "error", err)
+		}
+		return
+	}
+
+	h.mu.Lock()
+	if gameRoom, ok := h.clients[roomID.String()]; ok {
+		stopClock(gameRoom)
+		stopShuffle(gameRoom)
+		delete(h.clients, roomID.String())
+	}
+	h.mu.Unlock()
+
+	h.finishRoom(ctx, roomID)
+}
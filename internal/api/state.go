@@ -0,0 +1,236 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/JoaoRafa19/truco-backend-go/internal/game"
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type seatState struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Seat      int32     `json:"seat"`
+	Team      int32     `json:"team"`
+	Ready     bool      `json:"ready"`
+	CardCount int       `json:"card_count"`
+}
+
+type raiseState struct {
+	Level    int    `json:"level"`
+	Value    int    `json:"value"`
+	Pending  bool   `json:"pending"`
+	Caller   int    `json:"caller"`
+	NextCall string `json:"next_call,omitempty"`
+}
+
+type clockState struct {
+	Player    uuid.UUID          `json:"player"`
+	Action    game.TimeoutAction `json:"action"`
+	Deadline  time.Time          `json:"deadline"`
+	Remaining int                `json:"remaining"`
+}
+
+// gameState é a mesa vista por um jogador: as cartas dos outros aparecem só
+// como quantidade
+type gameState struct {
+	ID       uuid.UUID    `json:"id"`
+	State    string       `json:"state"`
+	Status   string       `json:"status"`
+	Variant  string       `json:"variant"`
+	Mode     string       `json:"mode"`
+	Round    int32        `json:"round"`
+	Players  []seatState  `json:"players"`
+	Score    [2]int       `json:"score"`
+	Finished bool         `json:"finished"`
+	Winner   int          `json:"winner"`
+	Vira     *deck.Card   `json:"vira,omitempty"`
+	Special  game.Special `json:"special,omitempty"`
+
+	// Campos da mão em andamento
+	InProgress       bool                      `json:"in_progress"`
+	Mano             int                       `json:"mano"`
+	Turn             int                       `json:"turn"`
+	Raise            *raiseState               `json:"raise,omitempty"`
+	Tricks           []game.Trick              `json:"tricks"`
+	TricksWon        [2]int                    `json:"tricks_won"`
+	Envido           *game.Envido              `json:"envido,omitempty"`
+	AwaitingDecision bool                      `json:"awaiting_decision"`
+	Clock            *clockState               `json:"clock,omitempty"`
+	Cards            []deck.Card               `json:"cards"`
+	PartnersCards    map[uuid.UUID][]deck.Card `json:"partners_cards,omitempty"`
+}
+
+// getGameState retorna o estado da mesa para o jogador redesenhar a tela
+// depois de recarregar a página
+func (h apiHandler) getGameState(w http.ResponseWriter, r *http.Request) {
+	roomID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+	if err != nil {
+		returnError(w, http.StatusBadRequest)
+		return
+	}
+
+	playerID, _, err := h.GetPlayerAndRoom(r, w, roomID)
+	if err != nil {
+		return
+	}
+
+	room, err := h.q.GetRoom(r.Context(), roomID)
+	if err != nil {
+		returnError(w, http.StatusNotFound)
+		return
+	}
+
+	seats, err := h.q.GetRoomSeats(r.Context(), roomID)
+	if err != nil {
+		slog.Error("GetGameState", "error", err)
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	state := gameState{
+		ID:      room.ID,
+		State:   string(room.State),
+		Status:  string(room.Status),
+		Variant: string(room.Variant),
+		Mode:    game.Mode(int(room.TeamSize)),
+		Round:   room.Round,
+		Players: make([]seatState, len(seats)),
+		Winner:  game.Tie,
+		Turn:    game.Tie,
+	}
+	for i, seat := range seats {
+		state.Players[i] = seatState{ID: seat.ID, Name: seat.Name, Seat: seat.Ordem, Team: seat.Team, Ready: seat.Ready}
+	}
+
+	h.mu.Lock()
+	gameRoom, ok := h.clients[roomID.String()]
+	if ok && gameRoom.hand != nil {
+		h.handState(&state, gameRoom, playerID)
+	}
+	h.mu.Unlock()
+
+	if !state.InProgress {
+		// a sala não está em memória: usa o que foi salvo no banco
+		if err := h.savedState(r, &state, room, playerID); err != nil {
+			slog.Error("GetGameState", "error", err)
+			returnError(w, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	result, err := json.Marshal(state)
+	if err != nil {
+		returnError(w, http.StatusInternalServerError)
+		return
+	}
+
+	returnData(result, w)
+}
+
+// handState preenche o estado com a mão em memória. Deve ser chamado com h.mu travado
+func (h apiHandler) handState(state *gameState, room *Room, playerID uuid.UUID) {
+	hand := room.hand
+
+	state.InProgress = true
+	state.Round = room.round
+	state.Vira = hand.Vira
+	state.Special = hand.Special
+	state.Mano = hand.Mano
+	state.Turn = hand.Turn
+	// o estado é serializado depois de soltar h.mu, então nada pode apontar
+	// para a mão que continua sendo jogada
+	state.Tricks = make([]game.Trick, len(hand.Tricks))
+	for i, trick := range hand.Tricks {
+		state.Tricks[i] = trick.Clone()
+	}
+	state.TricksWon = hand.TricksWon()
+	state.AwaitingDecision = hand.AwaitingDecision
+	state.Raise = &raiseState{
+		Level:    hand.Raise.Level,
+		Value:    hand.Value(),
+		Pending:  hand.Raise.Pending,
+		Caller:   hand.Raise.Caller,
+		NextCall: hand.NextCall(),
+	}
+	if hand.Rules.Envido {
+		envido := hand.Envido.Clone()
+		state.Envido = &envido
+	}
+
+	if room.match != nil {
+		state.Score = room.match.Score
+		state.Finished = room.match.Finished
+		state.Winner = room.match.Winner
+	}
+
+	if room.clock != nil {
+		state.Clock = &clockState{
+			Player:    room.clock.player,
+			Action:    room.clock.action,
+			Deadline:  room.clock.deadline,
+			Remaining: room.clock.remaining(),
+		}
+	}
+
+	for i := range state.Players {
+		state.Players[i].CardCount = len(hand.Cards[state.Players[i].ID])
+	}
+
+	// na mão de ferro nem o próprio jogador vê as cartas
+	if !hand.Blind {
+		state.Cards = hand.Cards[playerID]
+	}
+
+	seat, err := hand.Seat(playerID)
+	if err == nil && hand.Special == game.MaoDeOnze && game.Team(seat) == hand.ElevenTeam {
+		state.PartnersCards = make(map[uuid.UUID][]deck.Card)
+		for _, partner := range hand.Partners(playerID) {
+			state.PartnersCards[partner] = hand.Cards[partner]
+		}
+	}
+}
+
+// savedState preenche o estado a partir de games.result, games.vira e das mãos salvas
+func (h apiHandler) savedState(r *http.Request, state *gameState, room pgstore.Game, playerID uuid.UUID) error {
+	if len(room.Result) > 0 {
+		var match game.Match
+		if err := json.Unmarshal(room.Result, &match); err != nil {
+			return err
+		}
+		state.Score = match.Score
+		state.Finished = match.Finished
+		state.Winner = match.Winner
+	}
+
+	if len(room.Vira) > 0 {
+		if err := json.Unmarshal(room.Vira, &state.Vira); err != nil {
+			return err
+		}
+	}
+
+	for i, seat := range state.Players {
+		playerHand, err := h.q.GetPlayerHand(r.Context(), pgstore.GetPlayerHandParams{PlayerID: seat.ID, Round: room.Round})
+		if err != nil {
+			// a mão ainda não foi distribuída
+			continue
+		}
+
+		var cards []deck.Card
+		if err := json.Unmarshal(playerHand.Cards, &cards); err != nil {
+			return err
+		}
+		state.Players[i].CardCount = len(cards)
+		if seat.ID == playerID {
+			state.Cards = cards
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package deck
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/google/uuid"
+)
+
+const imageBaseURL = "https://deckofcardsapi.com/static/img"
+
+var (
+	ErrNotEnoughCards = errors.New("not enough cards in the deck")
+	ErrDeckNotFound   = errors.New("deck not found")
+)
+
+type Card struct {
+	Code     string `json:"code"`
+	ImageURL string `json:"image"`
+	Value    string `json:"value"`
+	Suit     string `json:"suit"`
+}
+
+type Deck struct {
+	DeckID    string `json:"deck_id"`
+	Shuffled  bool   `json:"shuffled"`
+	Remaining int    `json:"remaining"`
+	Seed      int64  `json:"seed"`
+	Cards     []Card `json:"-"`
+	// Composition são todas as cartas do baralho, recolhidas a cada embaralhamento
+	Composition []Card `json:"-"`
+}
+
+var (
+	// Truco usa o baralho espanhol: sem 8, 9 e 10
+	values = []struct{ code, name string }{
+		{"A", "ACE"},
+		{"2", "2"},
+		{"3", "3"},
+		{"4", "4"},
+		{"5", "5"},
+		{"6", "6"},
+		{"7", "7"},
+		{"Q", "QUEEN"},
+		{"J", "JACK"},
+		{"K", "KING"},
+	}
+	suits = []struct{ code, name string }{
+		{"S", "SPADES"},
+		{"D", "DIAMONDS"},
+		{"C", "CLUBS"},
+		{"H", "HEARTS"},
+	}
+)
+
+func NewCard(value, suit string) (Card, error) {
+	for _, v := range values {
+		if v.code != value && v.name != value {
+			continue
+		}
+		for _, s := range suits {
+			if s.code != suit && s.name != suit {
+				continue
+			}
+			code := v.code + s.code
+			return Card{
+				Code:     code,
+				ImageURL: fmt.Sprintf("%s/%s.png", imageBaseURL, code),
+				Value:    v.name,
+				Suit:     s.name,
+			}, nil
+		}
+	}
+	return Card{}, fmt.Errorf("invalid card %s%s", value, suit)
+}
+
+func ParseCard(code string) (Card, error) {
+	if len(code) != 2 {
+		return Card{}, fmt.Errorf("invalid card %s", code)
+	}
+	return NewCard(code[:1], code[1:])
+}
+
+// TrucoCards retorna as 40 cartas do baralho de truco, sem embaralhar
+func TrucoCards() []Card {
+	codes := make([]string, len(values))
+	for i, v := range values {
+		codes[i] = v.code
+	}
+	return CardsOf(codes)
+}
+
+// CardsOf retorna as cartas dos valores informados nos quatro naipes, sem
+// embaralhar. Valores fora do baralho de truco são ignorados
+func CardsOf(codes []string) []Card {
+	cards := make([]Card, 0, len(codes)*len(suits))
+	for _, s := range suits {
+		for _, code := range codes {
+			if card, err := NewCard(code, s.code); err == nil {
+				cards = append(cards, card)
+			}
+		}
+	}
+	return cards
+}
+
+// NewDeck cria um baralho com as cartas de composition embaralhado a partir de
+// seed. Com seed 0 uma nova seed aleatória é sorteada e fica registrada no baralho
+func NewDeck(composition []Card, seed int64) *Deck {
+	d := &Deck{
+		DeckID:      uuid.NewString(),
+		Composition: composition,
+	}
+	d.Shuffle(seed)
+
+	return d
+}
+
+// Shuffle recolhe todas as cartas e embaralha de forma determinística a partir de seed
+func (d *Deck) Shuffle(seed int64) {
+	if seed == 0 {
+		seed = NewSeed()
+	}
+	d.Cards = ShuffledCards(d.Composition, seed)
+	d.Seed = seed
+	d.Shuffled = true
+	d.Remaining = len(d.Cards)
+}
+
+func NewSeed() int64 {
+	for {
+		if seed := rand.Int64(); seed != 0 {
+			return seed
+		}
+	}
+}
+
+// ShuffledCards reconstrói a ordem exata das cartas embaralhadas com seed,
+// permitindo reproduzir uma mão a partir da seed salva no jogo
+func ShuffledCards(composition []Card, seed int64) []Card {
+	cards := append([]Card(nil), composition...)
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed)>>32))
+	rng.Shuffle(len(cards), func(i, j int) {
+		cards[i], cards[j] = cards[j], cards[i]
+	})
+	return cards
+}
+
+func (d *Deck) DrawCards(numberOfCards int) ([]Card, error) {
+	if numberOfCards < 0 || numberOfCards > len(d.Cards) {
+		return nil, ErrNotEnoughCards
+	}
+
+	cards := make([]Card, numberOfCards)
+	copy(cards, d.Cards[:numberOfCards])
+	d.Cards = d.Cards[numberOfCards:]
+	d.Remaining = len(d.Cards)
+
+	return cards, nil
+}
+
+func (d *Deck) ReturnCards(cards []Card) {
+	d.Cards = append(d.Cards, cards...)
+	d.Remaining = len(d.Cards)
+}
+
+func (d *Deck) GetDeckState() Deck {
+	return Deck{
+		DeckID:    d.DeckID,
+		Shuffled:  d.Shuffled,
+		Remaining: d.Remaining,
+		Seed:      d.Seed,
+	}
+}
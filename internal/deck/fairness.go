@@ -0,0 +1,61 @@
+package deck
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrCommitmentMismatch = errors.New("server seed does not match commitment")
+
+// Commitment implementa o esquema commit-reveal do embaralhamento: o hash da
+// seed do servidor é publicado no começo da mão e a seed só é revelada no fim
+type Commitment struct {
+	ServerSeed string `json:"server_seed"`
+	Hash       string `json:"hash"`
+}
+
+func NewCommitment() (Commitment, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return Commitment{}, err
+	}
+
+	serverSeed := hex.EncodeToString(buf)
+	return Commitment{
+		ServerSeed: serverSeed,
+		Hash:       CommitmentHash(serverSeed),
+	}, nil
+}
+
+func CommitmentHash(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Seed combina a seed do servidor com as seeds enviadas pelos clientes na
+// seed usada para embaralhar o baralho
+func (c Commitment) Seed(clientSeeds []string) int64 {
+	return CombineSeeds(c.ServerSeed, clientSeeds)
+}
+
+func CombineSeeds(serverSeed string, clientSeeds []string) int64 {
+	sum := sha256.Sum256([]byte(serverSeed + ":" + strings.Join(clientSeeds, ":")))
+	seed := int64(binary.BigEndian.Uint64(sum[:8]))
+	if seed == 0 {
+		// 0 significa "sortear" para os providers
+		seed = 1
+	}
+	return seed
+}
+
+// VerifyShuffle confere a seed revelada contra o hash publicado e refaz o embaralhamento
+func VerifyShuffle(composition []Card, serverSeed, hash string, clientSeeds []string) ([]Card, error) {
+	if CommitmentHash(serverSeed) != hash {
+		return nil, ErrCommitmentMismatch
+	}
+	return ShuffledCards(composition, CombineSeeds(serverSeed, clientSeeds)), nil
+}
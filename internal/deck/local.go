@@ -0,0 +1,78 @@
+package deck
+
+import (
+	"context"
+	"sync"
+)
+
+type localProvider struct {
+	mu    sync.Mutex
+	store Store
+}
+
+// NewLocalProvider embaralha e distribui as cartas no próprio processo
+func NewLocalProvider(store Store) Provider {
+	return &localProvider{store: store}
+}
+
+func (p *localProvider) CreateDeck(ctx context.Context, composition []Card, seed int64) (Deck, error) {
+	d := NewDeck(composition, seed)
+	if err := p.store.CreateDeck(ctx, *d); err != nil {
+		return Deck{}, err
+	}
+	return d.GetDeckState(), nil
+}
+
+func (p *localProvider) DrawCards(ctx context.Context, deckID string, count int) ([]Card, error) {
+	var cards []Card
+	err := p.update(ctx, deckID, func(d *Deck) error {
+		var err error
+		cards, err = d.DrawCards(count)
+		return err
+	})
+	return cards, err
+}
+
+func (p *localProvider) GetDeckState(ctx context.Context, deckID string) (Deck, error) {
+	d, err := p.store.LoadDeck(ctx, deckID)
+	if err != nil {
+		return Deck{}, err
+	}
+	return d.GetDeckState(), nil
+}
+
+func (p *localProvider) ReturnCards(ctx context.Context, deckID string, cards []Card) (Deck, error) {
+	var state Deck
+	err := p.update(ctx, deckID, func(d *Deck) error {
+		d.ReturnCards(cards)
+		state = d.GetDeckState()
+		return nil
+	})
+	return state, err
+}
+
+func (p *localProvider) Reshuffle(ctx context.Context, deckID string, seed int64) (Deck, error) {
+	var state Deck
+	err := p.update(ctx, deckID, func(d *Deck) error {
+		d.Shuffle(seed)
+		state = d.GetDeckState()
+		return nil
+	})
+	return state, err
+}
+
+func (p *localProvider) update(ctx context.Context, deckID string, fn func(d *Deck) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	d, err := p.store.LoadDeck(ctx, deckID)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&d); err != nil {
+		return err
+	}
+
+	return p.store.SaveDeck(ctx, d)
+}
@@ -0,0 +1,80 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/store/pgstore"
+	"github.com/jackc/pgx/v5"
+)
+
+type pgStore struct {
+	q *pgstore.Queries
+}
+
+// NewPgStore guarda os baralhos na tabela decks, referenciada por games.deck_id
+func NewPgStore(q *pgstore.Queries) Store {
+	return pgStore{q: q}
+}
+
+func (s pgStore) CreateDeck(ctx context.Context, d Deck) error {
+	cards, err := json.Marshal(d.Cards)
+	if err != nil {
+		return err
+	}
+
+	composition, err := json.Marshal(d.Composition)
+	if err != nil {
+		return err
+	}
+
+	return s.q.CreateDeck(ctx, pgstore.CreateDeckParams{
+		ID:          d.DeckID,
+		Shuffled:    d.Shuffled,
+		Cards:       cards,
+		Seed:        d.Seed,
+		Composition: composition,
+	})
+}
+
+func (s pgStore) LoadDeck(ctx context.Context, deckID string) (Deck, error) {
+	row, err := s.q.GetDeck(ctx, deckID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Deck{}, ErrDeckNotFound
+		}
+		return Deck{}, err
+	}
+
+	var cards, composition []Card
+	if err := json.Unmarshal(row.Cards, &cards); err != nil {
+		return Deck{}, err
+	}
+	if err := json.Unmarshal(row.Composition, &composition); err != nil {
+		return Deck{}, err
+	}
+
+	return Deck{
+		DeckID:      row.ID,
+		Shuffled:    row.Shuffled,
+		Remaining:   len(cards),
+		Seed:        row.Seed,
+		Cards:       cards,
+		Composition: composition,
+	}, nil
+}
+
+func (s pgStore) SaveDeck(ctx context.Context, d Deck) error {
+	cards, err := json.Marshal(d.Cards)
+	if err != nil {
+		return err
+	}
+
+	return s.q.UpdateDeck(ctx, pgstore.UpdateDeckParams{
+		Shuffled: d.Shuffled,
+		Cards:    cards,
+		Seed:     d.Seed,
+		ID:       d.DeckID,
+	})
+}
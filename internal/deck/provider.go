@@ -0,0 +1,21 @@
+package deck
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrSeedUnsupported = errors.New("deck provider does not support seeded shuffles")
+
+// Provider abstrai onde o baralho de uma sala vive: em memória/banco (local)
+// ou em um serviço HTTP compatível com o deckofcardsapi.com.
+//
+// Seed 0 em CreateDeck e Reshuffle significa "sortear uma nova seed"; o
+// baralho retornado informa a seed usada para que a mão possa ser reproduzida
+type Provider interface {
+	CreateDeck(ctx context.Context, composition []Card, seed int64) (Deck, error)
+	DrawCards(ctx context.Context, deckID string, count int) ([]Card, error)
+	GetDeckState(ctx context.Context, deckID string) (Deck, error)
+	ReturnCards(ctx context.Context, deckID string, cards []Card) (Deck, error)
+	Reshuffle(ctx context.Context, deckID string, seed int64) (Deck, error)
+}
@@ -0,0 +1,120 @@
+package deck
+
+import "slices"
+
+// Força das cartas comuns no truco, da mais fraca para a mais forte
+var rankOrder = []string{"4", "5", "6", "7", "QUEEN", "JACK", "KING", "ACE", "2", "3"}
+
+// Naipes das manilhas, da mais fraca para a mais forte:
+// pica-fumo (ouros), espadilha (espadas), copas e zap (paus)
+var manilhaSuits = []string{"DIAMONDS", "SPADES", "HEARTS", "CLUBS"}
+
+var manilhaNames = map[string]string{
+	"DIAMONDS": "pica-fumo",
+	"SPADES":   "espadilha",
+	"HEARTS":   "copas",
+	"CLUBS":    "zap",
+}
+
+// Toda manilha é mais forte que qualquer carta comum
+const manilhaPower = 100
+
+// Ranking define a força de cada carta em uma mão
+type Ranking interface {
+	Power(card Card) int
+	IsManilha(card Card) bool
+}
+
+// Compare retorna 1 se a for mais forte que b, -1 se for mais fraca e 0 quando
+// as cartas empatam (cangam)
+func Compare(r Ranking, a, b Card) int {
+	pa, pb := r.Power(a), r.Power(b)
+	switch {
+	case pa > pb:
+		return 1
+	case pa < pb:
+		return -1
+	}
+	return 0
+}
+
+// ViraRanking é o ranking do truco paulista: as manilhas são as cartas do
+// valor seguinte ao da vira
+type ViraRanking struct {
+	Vira Card
+}
+
+func NewViraRanking(vira Card) ViraRanking {
+	return ViraRanking{Vira: vira}
+}
+
+// ManilhaValue é o valor da carta seguinte à vira; depois do 3 volta para o 4.
+// Uma vira fora do baralho de truco (ou sem vira) não define manilha e o
+// retorno é vazio, então todas as cartas seguem a ordem comum
+func (r ViraRanking) ManilhaValue() string {
+	i := slices.Index(rankOrder, r.Vira.Value)
+	if i < 0 {
+		return ""
+	}
+	return rankOrder[(i+1)%len(rankOrder)]
+}
+
+// Manilhas retorna as quatro manilhas da mão, da mais fraca (pica-fumo) para a mais forte (zap)
+func (r ViraRanking) Manilhas() []Card {
+	if r.ManilhaValue() == "" {
+		return nil
+	}
+	manilhas := make([]Card, 0, len(manilhaSuits))
+	for _, suit := range manilhaSuits {
+		card, _ := NewCard(r.ManilhaValue(), suit)
+		manilhas = append(manilhas, card)
+	}
+	return manilhas
+}
+
+func (r ViraRanking) IsManilha(card Card) bool {
+	value := r.ManilhaValue()
+	return value != "" && card.Value == value
+}
+
+func (r ViraRanking) Power(card Card) int {
+	if r.IsManilha(card) {
+		return manilhaPower + slices.Index(manilhaSuits, card.Suit)
+	}
+	return slices.Index(rankOrder, card.Value)
+}
+
+// ManilhaName retorna o nome da manilha (zap, copas, espadilha ou pica-fumo)
+func ManilhaName(card Card) string {
+	return manilhaNames[card.Suit]
+}
+
+// FixedRanking é usado nas variantes sem vira (mineiro, gaúcho e argentino):
+// as manilhas são sempre as mesmas cartas e as demais seguem a ordem comum
+type FixedRanking struct {
+	manilhas []string
+}
+
+// NewFixedRanking recebe os códigos das manilhas, da mais fraca para a mais forte
+func NewFixedRanking(manilhas ...string) FixedRanking {
+	return FixedRanking{manilhas: manilhas}
+}
+
+var (
+	// Truco mineiro: zap (4♣), copas (7♥), espadilha (A♠) e pica-fumo (7♦)
+	MineiroRanking = NewFixedRanking("7D", "AS", "7H", "4C")
+	// Truco gaúcho e argentino: espadão (A♠), bastião (A♣), 7 de espadas e 7 de ouros.
+	// Os ases e setes falsos ficam na posição comum do A e do 7
+	SpanishRanking = NewFixedRanking("7D", "7S", "AC", "AS")
+)
+
+func (r FixedRanking) IsManilha(card Card) bool {
+	return slices.Contains(r.manilhas, card.Code)
+}
+
+func (r FixedRanking) Power(card Card) int {
+	if i := slices.Index(r.manilhas, card.Code); i >= 0 {
+		return manilhaPower + i
+	}
+	return slices.Index(rankOrder, card.Value)
+}
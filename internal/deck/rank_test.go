@@ -0,0 +1,167 @@
+package deck
+
+import "testing"
+
+func card(t *testing.T, code string) Card {
+	t.Helper()
+	c, err := ParseCard(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestManilhaValue(t *testing.T) {
+	tests := []struct {
+		vira string
+		want string
+	}{
+		{"4C", "5"},
+		{"5C", "6"},
+		{"6C", "7"},
+		{"7C", "QUEEN"},
+		{"QC", "JACK"},
+		{"JC", "KING"},
+		{"KC", "ACE"},
+		{"AC", "2"},
+		{"2C", "3"},
+		{"3C", "4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vira, func(t *testing.T) {
+			if got := NewViraRanking(card(t, tt.vira)).ManilhaValue(); got != tt.want {
+				t.Errorf("ManilhaValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManilhaValueWithoutVira(t *testing.T) {
+	r := NewViraRanking(Card{})
+	if got := r.ManilhaValue(); got != "" {
+		t.Errorf("ManilhaValue() = %q, want empty", got)
+	}
+	if manilhas := r.Manilhas(); manilhas != nil {
+		t.Errorf("Manilhas() = %v, want nil", manilhas)
+	}
+	for _, c := range TrucoCards() {
+		if r.IsManilha(c) {
+			t.Errorf("IsManilha(%s) = true without vira", c.Code)
+		}
+	}
+}
+
+func TestManilhaSuitOrder(t *testing.T) {
+	r := NewViraRanking(card(t, "3S"))
+	manilhas := r.Manilhas()
+
+	want := []string{"4D", "4S", "4H", "4C"}
+	names := []string{"pica-fumo", "espadilha", "copas", "zap"}
+	if len(manilhas) != len(want) {
+		t.Fatalf("Manilhas() = %v, want %v", manilhas, want)
+	}
+	for i, m := range manilhas {
+		if m.Code != want[i] {
+			t.Errorf("Manilhas()[%d] = %s, want %s", i, m.Code, want[i])
+		}
+		if got := ManilhaName(m); got != names[i] {
+			t.Errorf("ManilhaName(%s) = %q, want %q", m.Code, got, names[i])
+		}
+		if i > 0 && Compare(r, m, manilhas[i-1]) != 1 {
+			t.Errorf("%s should beat %s", m.Code, manilhas[i-1].Code)
+		}
+	}
+}
+
+func TestManilhaBeatsOrdinaryCards(t *testing.T) {
+	for _, vira := range []string{"4H", "7D", "KS", "3C"} {
+		r := NewViraRanking(card(t, vira))
+		for _, m := range r.Manilhas() {
+			for _, c := range TrucoCards() {
+				if r.IsManilha(c) {
+					continue
+				}
+				if Compare(r, m, c) != 1 || Compare(r, c, m) != -1 {
+					t.Errorf("vira %s: manilha %s should beat %s", vira, m.Code, c.Code)
+				}
+			}
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	r := NewViraRanking(card(t, "7C"))
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"3 beats 2", "3S", "2H", 1},
+		{"2 beats ace", "2D", "AC", 1},
+		{"king beats jack", "KH", "JS", 1},
+		{"4 loses to 5", "4D", "5C", -1},
+		{"same value cangam", "3S", "3H", 0},
+		{"same value other suits cangam", "KD", "KC", 0},
+		{"manilha beats 3", "QD", "3C", 1},
+		{"zap beats copas", "QC", "QH", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compare(r, card(t, tt.a), card(t, tt.b)); got != tt.want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixedRanking(t *testing.T) {
+	tests := []struct {
+		name     string
+		ranking  FixedRanking
+		manilhas []string
+		// ordinary são cartas comuns, inclusive as de mesmo valor das manilhas
+		ordinary []string
+	}{
+		{
+			name:     "mineiro",
+			ranking:  MineiroRanking,
+			manilhas: []string{"7D", "AS", "7H", "4C"},
+			ordinary: []string{"4D", "7S", "7C", "AH", "3S", "2C"},
+		},
+		{
+			name:     "gaucho e argentino",
+			ranking:  SpanishRanking,
+			manilhas: []string{"7D", "7S", "AC", "AS"},
+			ordinary: []string{"7H", "7C", "AH", "AD", "3S", "2C"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, code := range tt.manilhas {
+				m := card(t, code)
+				if !tt.ranking.IsManilha(m) {
+					t.Errorf("IsManilha(%s) = false", code)
+				}
+				if i > 0 && Compare(tt.ranking, m, card(t, tt.manilhas[i-1])) != 1 {
+					t.Errorf("%s should beat %s", code, tt.manilhas[i-1])
+				}
+				for _, o := range tt.ordinary {
+					if Compare(tt.ranking, m, card(t, o)) != 1 {
+						t.Errorf("manilha %s should beat %s", code, o)
+					}
+				}
+			}
+			for _, o := range tt.ordinary {
+				if tt.ranking.IsManilha(card(t, o)) {
+					t.Errorf("IsManilha(%s) = true", o)
+				}
+			}
+			if Compare(tt.ranking, card(t, "3S"), card(t, "3H")) != 0 {
+				t.Error("3S and 3H should cangar")
+			}
+			if Compare(tt.ranking, card(t, "3S"), card(t, "2S")) != 1 {
+				t.Error("3 should beat 2")
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package deck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const DefaultRemoteURL = "https://www.deckofcardsapi.com"
+
+type remoteProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+type cardsApiResponse struct {
+	Success   bool   `json:"success"`
+	Error     string `json:"error"`
+	DeckID    string `json:"deck_id"`
+	Remaining int    `json:"remaining"`
+	Shuffled  bool   `json:"shuffled"`
+	Cards     []Card `json:"cards"`
+}
+
+// NewRemoteProvider usa uma API compatível com o deckofcardsapi.com hospedada em baseURL
+func NewRemoteProvider(baseURL string, client *http.Client) Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &remoteProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}
+}
+
+func (p *remoteProvider) CreateDeck(ctx context.Context, composition []Card, seed int64) (Deck, error) {
+	if seed != 0 {
+		return Deck{}, ErrSeedUnsupported
+	}
+
+	response, err := p.get(ctx, "/api/deck/new/shuffle/", url.Values{"cards": {cardCodes(composition)}})
+	if err != nil {
+		return Deck{}, fmt.Errorf("unable to get a new deck: %w", err)
+	}
+	return response.deck(), nil
+}
+
+func (p *remoteProvider) DrawCards(ctx context.Context, deckID string, count int) ([]Card, error) {
+	response, err := p.get(ctx, fmt.Sprintf("/api/deck/%s/draw/", deckID), url.Values{"count": {fmt.Sprint(count)}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get cards: %w", err)
+	}
+	if len(response.Cards) != count {
+		return nil, ErrNotEnoughCards
+	}
+	return response.Cards, nil
+}
+
+func (p *remoteProvider) GetDeckState(ctx context.Context, deckID string) (Deck, error) {
+	response, err := p.get(ctx, fmt.Sprintf("/api/deck/%s/", deckID), nil)
+	if err != nil {
+		return Deck{}, fmt.Errorf("unable to get deck state: %w", err)
+	}
+	return response.deck(), nil
+}
+
+func (p *remoteProvider) ReturnCards(ctx context.Context, deckID string, cards []Card) (Deck, error) {
+	response, err := p.get(ctx, fmt.Sprintf("/api/deck/%s/return/", deckID), url.Values{"cards": {cardCodes(cards)}})
+	if err != nil {
+		return Deck{}, fmt.Errorf("unable to return cards: %w", err)
+	}
+	return response.deck(), nil
+}
+
+func (p *remoteProvider) Reshuffle(ctx context.Context, deckID string, seed int64) (Deck, error) {
+	if seed != 0 {
+		return Deck{}, ErrSeedUnsupported
+	}
+
+	response, err := p.get(ctx, fmt.Sprintf("/api/deck/%s/shuffle/", deckID), nil)
+	if err != nil {
+		return Deck{}, fmt.Errorf("unable to shuffle deck: %w", err)
+	}
+	return response.deck(), nil
+}
+
+func (p *remoteProvider) get(ctx context.Context, path string, query url.Values) (cardsApiResponse, error) {
+	endpoint := p.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return cardsApiResponse{}, err
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return cardsApiResponse{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return cardsApiResponse{}, ErrDeckNotFound
+	}
+	if response.StatusCode != http.StatusOK {
+		return cardsApiResponse{}, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	var body cardsApiResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return cardsApiResponse{}, err
+	}
+
+	if !body.Success {
+		return cardsApiResponse{}, fmt.Errorf("deck api error: %s", body.Error)
+	}
+
+	return body, nil
+}
+
+func (r cardsApiResponse) deck() Deck {
+	return Deck{
+		DeckID:    r.DeckID,
+		Shuffled:  r.Shuffled,
+		Remaining: r.Remaining,
+	}
+}
+
+func cardCodes(cards []Card) string {
+	codes := make([]string, len(cards))
+	for i, card := range cards {
+		codes[i] = card.Code
+	}
+	return strings.Join(codes, ",")
+}
@@ -0,0 +1,148 @@
+package deck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeDeckAPI responde toda requisição com status e body fixos e guarda a
+// última requisição recebida
+func fakeDeckAPI(t *testing.T, status int, body string) (*httptest.Server, *http.Request) {
+	t.Helper()
+	last := new(http.Request)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, last
+}
+
+func TestRemoteDrawCards(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		count  int
+		want   []string
+		err    error
+	}{
+		{
+			name:   "draw",
+			status: http.StatusOK,
+			body:   `{"success":true,"deck_id":"abc","remaining":38,"cards":[{"code":"3S","value":"3","suit":"SPADES"},{"code":"KH","value":"KING","suit":"HEARTS"}]}`,
+			count:  2,
+			want:   []string{"3S", "KH"},
+		},
+		{
+			name:   "short draw",
+			status: http.StatusOK,
+			body:   `{"success":true,"deck_id":"abc","remaining":0,"cards":[{"code":"3S","value":"3","suit":"SPADES"}]}`,
+			count:  2,
+			err:    ErrNotEnoughCards,
+		},
+		{
+			name:   "unknown deck",
+			status: http.StatusNotFound,
+			body:   `{"success":false,"error":"Deck ID does not exist."}`,
+			count:  1,
+			err:    ErrDeckNotFound,
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			body:   `oops`,
+			count:  1,
+		},
+		{
+			name:   "bad gateway",
+			status: http.StatusBadGateway,
+			count:  1,
+		},
+		{
+			name:   "malformed json",
+			status: http.StatusOK,
+			body:   `{"success":true,"cards":[`,
+			count:  1,
+		},
+		{
+			name:   "api error",
+			status: http.StatusOK,
+			body:   `{"success":false,"error":"Not enough cards remaining to draw 3 additional"}`,
+			count:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, last := fakeDeckAPI(t, tt.status, tt.body)
+			provider := NewRemoteProvider(server.URL+"/", server.Client())
+
+			cards, err := provider.DrawCards(context.Background(), "abc", tt.count)
+			if last.URL.Path != "/api/deck/abc/draw/" {
+				t.Errorf("path = %q", last.URL.Path)
+			}
+
+			if tt.want == nil {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if tt.err != nil && !errors.Is(err, tt.err) {
+					t.Errorf("err = %v, want %v", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cards) != len(tt.want) {
+				t.Fatalf("drew %d cards, want %d", len(cards), len(tt.want))
+			}
+			for i, card := range cards {
+				if card.Code != tt.want[i] {
+					t.Errorf("card %d = %s, want %s", i, card.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRemoteCreateDeck(t *testing.T) {
+	server, last := fakeDeckAPI(t, http.StatusOK, `{"success":true,"deck_id":"abc","remaining":40,"shuffled":true}`)
+	provider := NewRemoteProvider(server.URL, server.Client())
+
+	d, err := provider.CreateDeck(context.Background(), []Card{card(t, "3S"), card(t, "KH")}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.DeckID != "abc" || d.Remaining != 40 || !d.Shuffled {
+		t.Errorf("deck = %+v", d)
+	}
+	if got := last.URL.Query().Get("cards"); got != "3S,KH" {
+		t.Errorf("cards = %q, want the composition", got)
+	}
+}
+
+func TestRemoteSeedUnsupported(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+	provider := NewRemoteProvider(server.URL, server.Client())
+
+	if _, err := provider.CreateDeck(context.Background(), TrucoCards(), 42); err != ErrSeedUnsupported {
+		t.Errorf("CreateDeck: %v, want %v", err, ErrSeedUnsupported)
+	}
+	if _, err := provider.Reshuffle(context.Background(), "abc", 42); err != ErrSeedUnsupported {
+		t.Errorf("Reshuffle: %v, want %v", err, ErrSeedUnsupported)
+	}
+	if called {
+		t.Error("a seeded shuffle should not reach the remote api")
+	}
+}
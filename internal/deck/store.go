@@ -0,0 +1,49 @@
+package deck
+
+import (
+	"context"
+	"sync"
+)
+
+// Store persiste o estado completo de um baralho local, incluindo as cartas restantes
+type Store interface {
+	CreateDeck(ctx context.Context, d Deck) error
+	LoadDeck(ctx context.Context, deckID string) (Deck, error)
+	SaveDeck(ctx context.Context, d Deck) error
+}
+
+type memoryStore struct {
+	mu    sync.Mutex
+	decks map[string]Deck
+}
+
+func NewMemoryStore() Store {
+	return &memoryStore{decks: make(map[string]Deck)}
+}
+
+func (s *memoryStore) CreateDeck(_ context.Context, d Deck) error {
+	return s.SaveDeck(context.Background(), d)
+}
+
+func (s *memoryStore) LoadDeck(_ context.Context, deckID string) (Deck, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.decks[deckID]
+	if !ok {
+		return Deck{}, ErrDeckNotFound
+	}
+	d.Cards = append([]Card(nil), d.Cards...)
+	d.Composition = append([]Card(nil), d.Composition...)
+	return d, nil
+}
+
+func (s *memoryStore) SaveDeck(_ context.Context, d Deck) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d.Cards = append([]Card(nil), d.Cards...)
+	d.Composition = append([]Card(nil), d.Composition...)
+	s.decks[d.DeckID] = d
+	return nil
+}
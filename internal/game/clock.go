@@ -0,0 +1,61 @@
+package game
+
+import "github.com/google/uuid"
+
+// TimeoutAction é o que o servidor faz pelo jogador quando o tempo da jogada acaba
+type TimeoutAction string
+
+const (
+	// TimeoutPlay joga a carta mais fraca do jogador
+	TimeoutPlay TimeoutAction = "play"
+	// TimeoutDecline corre do truco pendente
+	TimeoutDecline TimeoutAction = "decline"
+	// TimeoutDeclineEnvido recusa o envido pendente
+	TimeoutDeclineEnvido TimeoutAction = "decline envido"
+	// TimeoutRun corre da mão de onze
+	TimeoutRun TimeoutAction = "run"
+)
+
+// OnTheClock retorna quem a mesa está esperando e o que acontece se o tempo
+// acabar. ok é falso quando a mão já terminou
+func (h *Hand) OnTheClock() (uuid.UUID, TimeoutAction, bool) {
+	switch {
+	case h.Done:
+		return uuid.Nil, "", false
+	case h.AwaitingDecision:
+		return h.firstOfTeam(h.ElevenTeam), TimeoutRun, true
+	case h.Envido.Pending:
+		return h.firstOfTeam(1 - h.Envido.Caller), TimeoutDeclineEnvido, true
+	case h.Raise.Pending:
+		return h.firstOfTeam(1 - h.Raise.Caller), TimeoutDecline, true
+	}
+	return h.Players[h.Turn], TimeoutPlay, true
+}
+
+// firstOfTeam é o primeiro jogador do time a partir da vez, quem responde pelo
+// time quando o tempo acaba
+func (h *Hand) firstOfTeam(team int) uuid.UUID {
+	for i := range h.Players {
+		seat := (h.Turn + i) % len(h.Players)
+		if Team(seat) == team {
+			return h.Players[seat]
+		}
+	}
+	return uuid.Nil
+}
+
+// LowestCard retorna o código da carta mais fraca na mão do jogador
+func (h *Hand) LowestCard(player uuid.UUID) (string, error) {
+	cards := h.Cards[player]
+	if len(cards) == 0 {
+		return "", ErrCardNotInHand
+	}
+
+	lowest := cards[0]
+	for _, card := range cards[1:] {
+		if h.Ranking.Power(card) < h.Ranking.Power(lowest) {
+			lowest = card
+		}
+	}
+	return lowest.Code, nil
+}
@@ -0,0 +1,390 @@
+package game
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/google/uuid"
+)
+
+type EnvidoCall string
+
+const (
+	CallEnvido      EnvidoCall = "envido"
+	CallRealEnvido  EnvidoCall = "real envido"
+	CallFaltaEnvido EnvidoCall = "falta envido"
+	CallFlor        EnvidoCall = "flor"
+	// CallContraFlor e CallContraFlorAlResto são os aumentos de quem também tem flor
+	CallContraFlor        EnvidoCall = "contraflor"
+	CallContraFlorAlResto EnvidoCall = "contraflor al resto"
+)
+
+const (
+	// envidoBase é somado aos pontos quando duas ou três cartas são do mesmo naipe
+	envidoBase = 20
+	florPoints = 3
+	// contraFlorPoints é o valor da contraflor aceita
+	contraFlorPoints = 6
+)
+
+// Envido é a aposta paralela ao truco do gaúcho e do argentino. Os pontos são
+// somados ao placar assim que a aposta se resolve, sem depender da mão
+type Envido struct {
+	// Calls são os pedidos feitos até agora, na ordem (envido, envido, real envido...)
+	Calls   []EnvidoCall `json:"calls"`
+	Pending bool         `json:"pending"`
+	// Caller é o time que fez o último pedido
+	Caller int  `json:"caller"`
+	Flor   bool `json:"flor"`
+	Done   bool `json:"done"`
+	// Winner é o time que leva os pontos do envido
+	Winner       int           `json:"winner"`
+	Points       int           `json:"points"`
+	Declarations []Declaration `json:"declarations,omitempty"`
+}
+
+// Clone copia o envido com os próprios pedidos e cantos, para ser lido fora da
+// trava da sala
+func (e Envido) Clone() Envido {
+	e.Calls = slices.Clone(e.Calls)
+	e.Declarations = slices.Clone(e.Declarations)
+	return e
+}
+
+// Declaration é o canto dos pontos de um jogador. Quem não tem pontos para
+// superar o adversário diz "são boas" e não mostra os pontos
+type Declaration struct {
+	Player  uuid.UUID `json:"player"`
+	Seat    int       `json:"seat"`
+	Team    int       `json:"team"`
+	Points  int       `json:"points,omitempty"`
+	SaoBoas bool      `json:"sao_boas"`
+}
+
+// cardEnvido é o valor da carta no envido: as figuras valem zero
+func cardEnvido(card deck.Card) int {
+	if card.Value == "ACE" {
+		return 1
+	}
+	n, err := strconv.Atoi(card.Value)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// EnvidoPoints calcula os pontos de envido das cartas recebidas: duas cartas do
+// mesmo naipe valem 20 mais a soma das duas, senão vale a carta mais alta
+func EnvidoPoints(cards []deck.Card) int {
+	best := 0
+	for i, a := range cards {
+		best = max(best, cardEnvido(a))
+		for _, b := range cards[i+1:] {
+			if a.Suit == b.Suit {
+				best = max(best, envidoBase+cardEnvido(a)+cardEnvido(b))
+			}
+		}
+	}
+	return best
+}
+
+// HasFlor informa se as três cartas são do mesmo naipe
+func HasFlor(cards []deck.Card) bool {
+	if len(cards) < CardsPerPlayer {
+		return false
+	}
+	for _, card := range cards[1:] {
+		if card.Suit != cards[0].Suit {
+			return false
+		}
+	}
+	return true
+}
+
+// FlorPoints são 20 mais a soma das três cartas
+func FlorPoints(cards []deck.Card) int {
+	points := envidoBase
+	for _, card := range cards {
+		points += cardEnvido(card)
+	}
+	return points
+}
+
+// CallEnvido faz um pedido de envido, real envido, falta envido ou flor. Só vale
+// na primeira vaza, antes de o jogador jogar a sua carta. Com um envido
+// pendente, o time adversário pode aumentar com um pedido maior; com uma flor
+// pendente, o adversário que também tem flor aumenta com contraflor ou
+// contraflor al resto
+func (h *Hand) CallEnvido(player uuid.UUID, call EnvidoCall) error {
+	if !h.Rules.Envido {
+		return ErrEnvidoNotAllowed
+	}
+	if h.Done {
+		return ErrHandOver
+	}
+	if h.AwaitingDecision {
+		return ErrAwaitingDecision
+	}
+	if h.Envido.Done {
+		return ErrEnvidoOver
+	}
+
+	seat, err := h.Seat(player)
+	if err != nil {
+		return err
+	}
+	team := Team(seat)
+
+	if h.Envido.Pending {
+		if team == h.Envido.Caller && call != CallFlor {
+			return ErrEnvidoPending
+		}
+	} else if !h.canCallEnvido(seat) {
+		return ErrEnvidoOver
+	}
+
+	switch call {
+	case CallFlor:
+		return h.callFlor(player, team)
+	case CallContraFlor, CallContraFlorAlResto:
+		return h.raiseFlor(player, team, call)
+	}
+	// a flor cancela o envido: depois dela só há os aumentos da flor
+	if h.Envido.Flor {
+		return ErrInvalidEnvidoCall
+	}
+
+	// depois que o truco foi aceito não tem mais envido
+	if h.Raise.Level > 0 {
+		return ErrEnvidoOver
+	}
+	if !canFollow(h.Envido.Calls, call) {
+		return ErrInvalidEnvidoCall
+	}
+
+	h.Envido.Calls = append(h.Envido.Calls, call)
+	h.Envido.Pending = true
+	h.Envido.Caller = team
+	return nil
+}
+
+// canCallEnvido: ainda na primeira vaza e o jogador ainda não jogou
+func (h *Hand) canCallEnvido(seat int) bool {
+	if len(h.Tricks) > 1 {
+		return false
+	}
+	trick := h.CurrentTrick()
+	if trick == nil {
+		return true
+	}
+	if trick.Done {
+		return false
+	}
+	return !slices.ContainsFunc(trick.Plays, func(p Play) bool { return p.Seat == seat })
+}
+
+// canFollow valida a sequência dos pedidos: envido pode ser repetido uma vez,
+// real envido só uma vez e falta envido encerra a sequência
+func canFollow(calls []EnvidoCall, next EnvidoCall) bool {
+	rank := map[EnvidoCall]int{CallEnvido: 0, CallRealEnvido: 1, CallFaltaEnvido: 2}
+	limit := map[EnvidoCall]int{CallEnvido: 2, CallRealEnvido: 1, CallFaltaEnvido: 1}
+
+	if _, ok := rank[next]; !ok {
+		return false
+	}
+	if len(calls) > 0 && rank[next] < rank[calls[len(calls)-1]] {
+		return false
+	}
+
+	count := 0
+	for _, call := range calls {
+		if call == next {
+			count++
+		}
+	}
+	return count < limit[next]
+}
+
+// EnvidoValue é quanto vale a sequência de pedidos se for aceita. A falta envido
+// e a contraflor al resto valem o que falta para o time que está na frente
+// ganhar a partida; a flor e a contraflor não somam com os pedidos anteriores
+func (h *Hand) EnvidoValue(calls []EnvidoCall) int {
+	points := 0
+	for _, call := range calls {
+		switch call {
+		case CallEnvido:
+			points += 2
+		case CallRealEnvido:
+			points += 3
+		case CallFaltaEnvido, CallContraFlorAlResto:
+			return h.Falta
+		case CallFlor:
+			points = florPoints
+		case CallContraFlor:
+			points = contraFlorPoints
+		}
+	}
+	return points
+}
+
+// RespondEnvido aceita ou recusa o envido ou a flor pendente. Para aumentar o
+// time faz um novo pedido com CallEnvido. Recusar o envido dá ao time que pediu
+// o valor dos pedidos já aceitos, ou um ponto se só houve um pedido
+func (h *Hand) RespondEnvido(player uuid.UUID, answer Answer) error {
+	if h.Done {
+		return ErrHandOver
+	}
+	if !h.Envido.Pending {
+		return ErrNoPendingEnvido
+	}
+
+	seat, err := h.Seat(player)
+	if err != nil {
+		return err
+	}
+	if Team(seat) == h.Envido.Caller {
+		return ErrOwnRaise
+	}
+	if h.Envido.Flor {
+		return h.respondFlor(player, answer)
+	}
+
+	switch answer {
+	case Accept:
+		h.Envido.Points = h.EnvidoValue(h.Envido.Calls)
+		h.Envido.Winner = h.declare(func(p uuid.UUID) (int, bool) {
+			return EnvidoPoints(h.Dealt[p]), true
+		})
+	case Decline:
+		h.Envido.Winner = h.Envido.Caller
+		h.Envido.Points = 1
+		if n := len(h.Envido.Calls); n > 1 {
+			h.Envido.Points = h.EnvidoValue(h.Envido.Calls[:n-1])
+		}
+	default:
+		return ErrInvalidAnswer
+	}
+
+	h.Envido.Pending = false
+	h.Envido.Done = true
+	return nil
+}
+
+// callFlor canta a flor e cancela o envido pendente. Se nenhum adversário tem
+// flor não há o que responder e a flor vale 3 na hora; senão ela fica pendente
+// esperando o adversário aceitar, recusar ou aumentar
+func (h *Hand) callFlor(player uuid.UUID, team int) error {
+	if h.Envido.Flor {
+		return ErrInvalidEnvidoCall
+	}
+	if !HasFlor(h.Dealt[player]) {
+		return ErrNoFlor
+	}
+
+	h.Envido.Calls = []EnvidoCall{CallFlor}
+	h.Envido.Flor = true
+	h.Envido.Caller = team
+
+	if !h.teamHasFlor(1 - team) {
+		h.Envido.Pending = false
+		h.Envido.Done = true
+		h.Envido.Winner = team
+		h.Envido.Points = florPoints
+		return nil
+	}
+
+	h.Envido.Pending = true
+	return nil
+}
+
+// raiseFlor aumenta a flor pendente. A contraflor al resto encerra os aumentos
+func (h *Hand) raiseFlor(player uuid.UUID, team int, call EnvidoCall) error {
+	if !h.Envido.Flor || !h.Envido.Pending {
+		return ErrInvalidEnvidoCall
+	}
+	if !HasFlor(h.Dealt[player]) {
+		return ErrNoFlor
+	}
+
+	last := h.Envido.Calls[len(h.Envido.Calls)-1]
+	if last == CallContraFlorAlResto || last == call {
+		return ErrInvalidEnvidoCall
+	}
+
+	h.Envido.Calls = append(h.Envido.Calls, call)
+	h.Envido.Caller = team
+	return nil
+}
+
+// respondFlor aceita ou recusa a flor pendente. Só aceita quem também tem flor,
+// e as flores são comparadas. Recusar dá ao time que pediu os 3 da flor, ou o
+// valor dos pedidos anteriores mais um quando a flor já tinha sido aumentada
+func (h *Hand) respondFlor(player uuid.UUID, answer Answer) error {
+	switch answer {
+	case Accept:
+		if !HasFlor(h.Dealt[player]) {
+			return ErrNoFlor
+		}
+		h.Envido.Points = h.EnvidoValue(h.Envido.Calls)
+		h.Envido.Winner = h.declare(func(p uuid.UUID) (int, bool) {
+			cards := h.Dealt[p]
+			return FlorPoints(cards), HasFlor(cards)
+		})
+	case Decline:
+		h.Envido.Winner = h.Envido.Caller
+		h.Envido.Points = florPoints
+		if n := len(h.Envido.Calls); n > 1 {
+			h.Envido.Points = h.EnvidoValue(h.Envido.Calls[:n-1]) + 1
+		}
+	default:
+		return ErrInvalidAnswer
+	}
+
+	h.Envido.Pending = false
+	h.Envido.Done = true
+	return nil
+}
+
+// teamHasFlor informa se algum jogador do time tem flor
+func (h *Hand) teamHasFlor(team int) bool {
+	for seat, player := range h.Players {
+		if Team(seat) == team && HasFlor(h.Dealt[player]) {
+			return true
+		}
+	}
+	return false
+}
+
+// declare faz os jogadores cantarem os pontos a partir do mano, na ordem das
+// cadeiras. Cada jogador só mostra os pontos se superar o time que está na
+// frente, senão diz "são boas". No empate vence quem cantou primeiro, ou seja,
+// quem está mais perto do mano
+func (h *Hand) declare(points func(uuid.UUID) (int, bool)) int {
+	h.Envido.Declarations = nil
+	leader, best := Tie, 0
+
+	for i := range h.Players {
+		seat := (h.Mano + i) % len(h.Players)
+		player := h.Players[seat]
+		value, ok := points(player)
+		if !ok {
+			continue
+		}
+
+		team := Team(seat)
+		declaration := Declaration{Player: player, Seat: seat, Team: team}
+		switch {
+		case leader == team:
+			continue
+		case leader == Tie || value > best:
+			leader, best = team, value
+			declaration.Points = value
+		default:
+			declaration.SaoBoas = true
+		}
+		h.Envido.Declarations = append(h.Envido.Declarations, declaration)
+	}
+
+	return leader
+}
@@ -0,0 +1,389 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+)
+
+func cards(t *testing.T, codes ...string) []deck.Card {
+	t.Helper()
+	parsed := make([]deck.Card, len(codes))
+	for i, code := range codes {
+		parsed[i] = parseCard(t, code)
+	}
+	return parsed
+}
+
+func TestEnvidoPoints(t *testing.T) {
+	tests := []struct {
+		cards []string
+		want  int
+		flor  bool
+	}{
+		{[]string{"7S", "6S", "KH"}, 33, false},
+		{[]string{"KS", "QS", "JH"}, 20, false},
+		{[]string{"7S", "5H", "4D"}, 7, false},
+		{[]string{"AS", "KH", "QD"}, 1, false},
+		{[]string{"7S", "6S", "5S"}, 33, true},
+	}
+
+	for _, tt := range tests {
+		hand := cards(t, tt.cards...)
+		if got := EnvidoPoints(hand); got != tt.want {
+			t.Errorf("EnvidoPoints(%v) = %d, want %d", tt.cards, got, tt.want)
+		}
+		if got := HasFlor(hand); got != tt.flor {
+			t.Errorf("HasFlor(%v) = %v, want %v", tt.cards, got, tt.flor)
+		}
+	}
+
+	if got := FlorPoints(cards(t, "7S", "6S", "5S")); got != 38 {
+		t.Errorf("FlorPoints = %d, want 38", got)
+	}
+}
+
+// callSequence faz os pedidos alternando os times a partir da cadeira 0
+func callSequence(t *testing.T, hand *Hand, calls ...EnvidoCall) {
+	t.Helper()
+	for i, call := range calls {
+		if err := hand.CallEnvido(hand.Players[i%2], call); err != nil {
+			t.Fatalf("call %d (%s): %v", i+1, call, err)
+		}
+	}
+}
+
+func newEnvidoHand(t *testing.T) *Hand {
+	t.Helper()
+	return newHand(t, Gaucho, "", []string{"7S", "6S", "KH"}, []string{"2H", "3H", "QD"})
+}
+
+func TestEnvidoValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		calls   []EnvidoCall
+		accept  int
+		decline int
+	}{
+		{"envido", []EnvidoCall{CallEnvido}, 2, 1},
+		{"envido envido", []EnvidoCall{CallEnvido, CallEnvido}, 4, 2},
+		{"real envido", []EnvidoCall{CallRealEnvido}, 3, 1},
+		{"envido real envido", []EnvidoCall{CallEnvido, CallRealEnvido}, 5, 2},
+		{"envido envido real envido", []EnvidoCall{CallEnvido, CallEnvido, CallRealEnvido}, 7, 4},
+		{"falta envido", []EnvidoCall{CallFaltaEnvido}, 24, 1},
+		{"envido falta envido", []EnvidoCall{CallEnvido, CallFaltaEnvido}, 24, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := newEnvidoHand(t)
+			callSequence(t, hand, tt.calls...)
+			caller := hand.Envido.Caller
+			if err := hand.RespondEnvido(hand.Players[1-caller], Accept); err != nil {
+				t.Fatalf("accept: %v", err)
+			}
+			if hand.Envido.Points != tt.accept {
+				t.Errorf("accepted points = %d, want %d", hand.Envido.Points, tt.accept)
+			}
+
+			hand = newEnvidoHand(t)
+			callSequence(t, hand, tt.calls...)
+			if err := hand.RespondEnvido(hand.Players[1-caller], Decline); err != nil {
+				t.Fatalf("decline: %v", err)
+			}
+			if hand.Envido.Points != tt.decline || hand.Envido.Winner != caller {
+				t.Errorf("declined = %d points to %d, want %d to %d", hand.Envido.Points, hand.Envido.Winner, tt.decline, caller)
+			}
+			if !hand.Envido.Done || hand.Envido.Pending {
+				t.Error("the envido should be over")
+			}
+		})
+	}
+}
+
+func TestEnvidoSequence(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls []EnvidoCall
+	}{
+		{"envido after real envido", []EnvidoCall{CallRealEnvido, CallEnvido}},
+		{"three envidos", []EnvidoCall{CallEnvido, CallEnvido, CallEnvido}},
+		{"real envido twice", []EnvidoCall{CallRealEnvido, CallRealEnvido}},
+		{"real envido after falta envido", []EnvidoCall{CallFaltaEnvido, CallRealEnvido}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := newEnvidoHand(t)
+			last := len(tt.calls) - 1
+			callSequence(t, hand, tt.calls[:last]...)
+			if err := hand.CallEnvido(hand.Players[last%2], tt.calls[last]); err != ErrInvalidEnvidoCall {
+				t.Errorf("got %v, want %v", err, ErrInvalidEnvidoCall)
+			}
+		})
+	}
+}
+
+func TestEnvidoDeclarations(t *testing.T) {
+	tests := []struct {
+		name   string
+		seats  [][]string
+		winner int
+		// points são os pontos cantados por cadeira, -1 para "são boas"
+		points map[int]int
+	}{
+		{
+			name:   "mano wins and the other says são boas",
+			seats:  [][]string{{"7S", "6S", "KH"}, {"2H", "3H", "QD"}},
+			winner: 0,
+			points: map[int]int{0: 33, 1: -1},
+		},
+		{
+			name:   "second player beats the mano",
+			seats:  [][]string{{"2S", "3S", "QD"}, {"7H", "6H", "KC"}},
+			winner: 1,
+			points: map[int]int{0: 25, 1: 33},
+		},
+		{
+			name:   "tie goes to the mano",
+			seats:  [][]string{{"7S", "3S", "KD"}, {"7H", "3H", "QC"}},
+			winner: 0,
+			points: map[int]int{0: 30, 1: -1},
+		},
+		{
+			name: "partners only sing to beat the opponents",
+			seats: [][]string{
+				{"2S", "3S", "KD"},
+				{"7H", "QH", "KC"},
+				{"4C", "5C", "QS"},
+				{"JD", "QD", "AH"},
+			},
+			winner: 0,
+			points: map[int]int{0: 25, 1: 27, 2: 29, 3: -1},
+		},
+		{
+			name: "leading team's partner stays quiet",
+			seats: [][]string{
+				{"2S", "3S", "KD"},
+				{"7H", "QH", "KC"},
+				{"4C", "2C", "QS"},
+				{"JD", "QD", "AH"},
+			},
+			winner: 1,
+			points: map[int]int{0: 25, 1: 27, 2: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := newHand(t, Gaucho, "", tt.seats...)
+			callSequence(t, hand, CallEnvido)
+			if err := hand.RespondEnvido(hand.Players[1], Accept); err != nil {
+				t.Fatalf("accept: %v", err)
+			}
+
+			if hand.Envido.Winner != tt.winner {
+				t.Errorf("winner = %d, want %d", hand.Envido.Winner, tt.winner)
+			}
+
+			got := make(map[int]int, len(hand.Envido.Declarations))
+			for _, declaration := range hand.Envido.Declarations {
+				if declaration.SaoBoas != (declaration.Points == 0) {
+					t.Errorf("seat %d: são boas should hide the points: %+v", declaration.Seat, declaration)
+				}
+				got[declaration.Seat] = declaration.Points
+				if declaration.SaoBoas {
+					got[declaration.Seat] = -1
+				}
+			}
+			if len(got) != len(tt.points) {
+				t.Errorf("declarations = %v, want %v", got, tt.points)
+			}
+			for seat, want := range tt.points {
+				if got[seat] != want {
+					t.Errorf("seat %d declared %d, want %d", seat, got[seat], want)
+				}
+			}
+
+			seats := make([]int, len(hand.Envido.Declarations))
+			for i, declaration := range hand.Envido.Declarations {
+				seats[i] = declaration.Seat
+			}
+			if !slices.IsSorted(seats) {
+				t.Errorf("declarations should follow the table from the mano: %v", seats)
+			}
+		})
+	}
+}
+
+func TestFlor(t *testing.T) {
+	// seat 0: flor de 38; seat 1: flor de 37; seat 2: flor de 25; seat 3: sem flor
+	florSeats := [][]string{{"7S", "6S", "5S"}, {"7H", "6H", "4H"}, {"2D", "3D", "QD"}, {"2C", "KH", "QS"}}
+
+	tests := []struct {
+		name  string
+		seats [][]string
+		// calls são os pedidos de flor alternando os times a partir da cadeira 0
+		calls  []EnvidoCall
+		answer Answer
+		winner int
+		points int
+	}{
+		{"accepted flor goes to the best flor", florSeats, []EnvidoCall{CallFlor}, Accept, 0, florPoints},
+		{"declined flor", florSeats, []EnvidoCall{CallFlor}, Decline, 0, florPoints},
+		{"accepted contraflor", florSeats, []EnvidoCall{CallFlor, CallContraFlor}, Accept, 0, contraFlorPoints},
+		{"declined contraflor", florSeats, []EnvidoCall{CallFlor, CallContraFlor}, Decline, 1, florPoints + 1},
+		{"accepted contraflor al resto", florSeats, []EnvidoCall{CallFlor, CallContraFlorAlResto}, Accept, 0, 24},
+		{"declined contraflor al resto", florSeats, []EnvidoCall{CallFlor, CallContraFlor, CallContraFlorAlResto}, Decline, 0, contraFlorPoints + 1},
+		{
+			name:   "caller loses the contraflor",
+			seats:  [][]string{{"2S", "3S", "QS"}, {"7H", "6H", "4H"}},
+			calls:  []EnvidoCall{CallFlor, CallContraFlor},
+			answer: Accept,
+			winner: 1,
+			points: contraFlorPoints,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := newHand(t, Gaucho, "", tt.seats...)
+			callSequence(t, hand, tt.calls...)
+			if !hand.Envido.Pending || hand.Envido.Done {
+				t.Fatal("a flor against another flor should wait for an answer")
+			}
+
+			answering := hand.Players[1-hand.Envido.Caller]
+			if err := hand.RespondEnvido(answering, tt.answer); err != nil {
+				t.Fatalf("%s: %v", tt.answer, err)
+			}
+			if !hand.Envido.Done || hand.Envido.Winner != tt.winner || hand.Envido.Points != tt.points {
+				t.Errorf("envido = %+v, want %d points to %d", hand.Envido, tt.points, tt.winner)
+			}
+		})
+	}
+
+	t.Run("flor without an opposing flor scores at once", func(t *testing.T) {
+		hand := newHand(t, Gaucho, "", []string{"7S", "6S", "5S"}, []string{"2H", "3H", "QD"})
+		if err := hand.CallEnvido(hand.Players[0], CallFlor); err != nil {
+			t.Fatalf("flor: %v", err)
+		}
+		if !hand.Envido.Done || hand.Envido.Winner != 0 || hand.Envido.Points != florPoints {
+			t.Errorf("envido = %+v, want %d points to 0", hand.Envido, florPoints)
+		}
+	})
+
+	t.Run("flor cancels the pending envido", func(t *testing.T) {
+		hand := newHand(t, Gaucho, "", []string{"7S", "6S", "KH"}, []string{"7H", "6H", "4H"})
+		callSequence(t, hand, CallEnvido)
+		if err := hand.CallEnvido(hand.Players[1], CallFlor); err != nil {
+			t.Fatalf("flor over envido: %v", err)
+		}
+		if !hand.Envido.Done || hand.Envido.Winner != 1 || !slices.Equal(hand.Envido.Calls, []EnvidoCall{CallFlor}) {
+			t.Errorf("envido = %+v, want the flor alone", hand.Envido)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		hand := newEnvidoHand(t)
+		if err := hand.CallEnvido(hand.Players[0], CallFlor); err != ErrNoFlor {
+			t.Errorf("flor without flor: %v, want %v", err, ErrNoFlor)
+		}
+		if err := hand.CallEnvido(hand.Players[0], CallContraFlor); err != ErrInvalidEnvidoCall {
+			t.Errorf("contraflor without a flor: %v, want %v", err, ErrInvalidEnvidoCall)
+		}
+
+		hand = newHand(t, Gaucho, "", florSeats...)
+		callSequence(t, hand, CallFlor)
+		if err := hand.CallEnvido(hand.Players[1], CallEnvido); err != ErrInvalidEnvidoCall {
+			t.Errorf("envido over a flor: %v, want %v", err, ErrInvalidEnvidoCall)
+		}
+		if err := hand.CallEnvido(hand.Players[3], CallContraFlor); err != ErrNoFlor {
+			t.Errorf("contraflor without flor: %v, want %v", err, ErrNoFlor)
+		}
+		if err := hand.RespondEnvido(hand.Players[3], Accept); err != ErrNoFlor {
+			t.Errorf("accepting without flor: %v, want %v", err, ErrNoFlor)
+		}
+
+		if err := hand.CallEnvido(hand.Players[1], CallContraFlor); err != nil {
+			t.Fatalf("contraflor: %v", err)
+		}
+		if err := hand.CallEnvido(hand.Players[1], CallContraFlorAlResto); err != ErrEnvidoPending {
+			t.Errorf("raising your own contraflor: %v, want %v", err, ErrEnvidoPending)
+		}
+		if err := hand.CallEnvido(hand.Players[2], CallContraFlorAlResto); err != nil {
+			t.Fatalf("contraflor al resto: %v", err)
+		}
+		if err := hand.CallEnvido(hand.Players[1], CallContraFlor); err != ErrInvalidEnvidoCall {
+			t.Errorf("raising after al resto: %v, want %v", err, ErrInvalidEnvidoCall)
+		}
+
+		// sem flor o time ainda pode correr, como faz o relógio
+		if err := hand.RespondEnvido(hand.Players[3], Decline); err != nil {
+			t.Errorf("declining without flor: %v", err)
+		}
+	})
+}
+
+func TestEnvidoErrors(t *testing.T) {
+	t.Run("variant without envido", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		if err := hand.CallEnvido(hand.Players[0], CallEnvido); err != ErrEnvidoNotAllowed {
+			t.Errorf("got %v, want %v", err, ErrEnvidoNotAllowed)
+		}
+	})
+
+	t.Run("after playing a card", func(t *testing.T) {
+		hand := newEnvidoHand(t)
+		if _, err := hand.Play(hand.Players[0], "7S"); err != nil {
+			t.Fatal(err)
+		}
+		if err := hand.CallEnvido(hand.Players[0], CallEnvido); err != ErrEnvidoOver {
+			t.Errorf("got %v, want %v", err, ErrEnvidoOver)
+		}
+		// quem ainda não jogou na primeira vaza pode pedir
+		if err := hand.CallEnvido(hand.Players[1], CallEnvido); err != nil {
+			t.Errorf("second player calling envido: %v", err)
+		}
+	})
+
+	t.Run("after the first trick", func(t *testing.T) {
+		hand := newEnvidoHand(t)
+		playTrick(t, hand, "7S", "2H")
+		if err := hand.CallEnvido(hand.Players[hand.Turn], CallEnvido); err != ErrEnvidoOver {
+			t.Errorf("got %v, want %v", err, ErrEnvidoOver)
+		}
+	})
+
+	t.Run("raising your own envido", func(t *testing.T) {
+		hand := newEnvidoHand(t)
+		callSequence(t, hand, CallEnvido)
+		if err := hand.CallEnvido(hand.Players[0], CallRealEnvido); err != ErrEnvidoPending {
+			t.Errorf("got %v, want %v", err, ErrEnvidoPending)
+		}
+		if err := hand.RespondEnvido(hand.Players[0], Accept); err != ErrOwnRaise {
+			t.Errorf("got %v, want %v", err, ErrOwnRaise)
+		}
+	})
+
+	t.Run("after the truco is accepted", func(t *testing.T) {
+		hand := newEnvidoHand(t)
+		hand.Call(hand.Players[0])
+		hand.Respond(hand.Players[1], Accept)
+		if err := hand.CallEnvido(hand.Players[0], CallEnvido); err != ErrEnvidoOver {
+			t.Errorf("got %v, want %v", err, ErrEnvidoOver)
+		}
+	})
+
+	t.Run("envido goes before the truco", func(t *testing.T) {
+		hand := newEnvidoHand(t)
+		hand.Call(hand.Players[0])
+		if err := hand.CallEnvido(hand.Players[1], CallEnvido); err != nil {
+			t.Fatalf("envido over a pending truco: %v", err)
+		}
+		if err := hand.Respond(hand.Players[1], Accept); err != ErrEnvidoPending {
+			t.Errorf("answering the truco first: %v, want %v", err, ErrEnvidoPending)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package game
+
+// Error é uma jogada inválida. Code identifica o erro para os clientes
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var (
+	ErrUnknownVariant   = &Error{"unknown_variant", "unknown truco variant"}
+	ErrNotEnoughPlayers = &Error{"not_enough_players", "not enough players to deal"}
+	ErrNotInHand        = &Error{"not_in_hand", "player is not in this hand"}
+	ErrHandOver         = &Error{"hand_over", "hand is over"}
+	ErrNotYourTurn      = &Error{"not_your_turn", "not your turn"}
+	ErrCardNotInHand    = &Error{"card_not_in_hand", "card is not in the player's hand"}
+
+	ErrRaisePending   = &Error{"raise_pending", "a raise is waiting for an answer"}
+	ErrNoPendingRaise = &Error{"no_pending_raise", "there is no raise to answer"}
+	ErrMaxRaise       = &Error{"max_raise", "the hand is already at the highest value"}
+	ErrNotYourRaise   = &Error{"not_your_raise", "your team made the last raise"}
+	ErrOwnRaise       = &Error{"own_raise", "a team cannot answer its own raise"}
+	ErrInvalidAnswer  = &Error{"invalid_answer", "answer must be accept, decline or raise"}
+
+	ErrRaiseNotAllowed  = &Error{"raise_not_allowed", "raises are not allowed in this hand"}
+	ErrAwaitingDecision = &Error{"awaiting_decision", "waiting for the mao de onze decision"}
+	ErrNoDecision       = &Error{"no_decision", "there is no mao de onze decision to make"}
+	ErrNotElevenTeam    = &Error{"not_eleven_team", "only the team on eleven decides"}
+	ErrMatchOver        = &Error{"match_over", "the match is over"}
+
+	ErrUnknownMode = &Error{"unknown_mode", "mode must be 1v1, 2v2 or 3v3"}
+	ErrUnknownTeam = &Error{"unknown_team", "team must be 0 or 1"}
+	ErrRoomFull    = &Error{"room_full", "the room is full"}
+	ErrTeamFull    = &Error{"team_full", "the team is full"}
+	ErrRoomNotFull = &Error{"room_not_full", "all seats must be filled to start"}
+
+	ErrEnvidoNotAllowed  = &Error{"envido_not_allowed", "envido is not played in this variant"}
+	ErrEnvidoOver        = &Error{"envido_over", "envido can only be called in the first trick"}
+	ErrEnvidoPending     = &Error{"envido_pending", "an envido is waiting for an answer"}
+	ErrNoPendingEnvido   = &Error{"no_pending_envido", "there is no envido to answer"}
+	ErrInvalidEnvidoCall = &Error{"invalid_envido_call", "this envido call cannot follow the previous ones"}
+	ErrNoFlor            = &Error{"no_flor", "player does not have flor"}
+)
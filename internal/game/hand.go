@@ -0,0 +1,126 @@
+package game
+
+import (
+	"slices"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/google/uuid"
+)
+
+const CardsPerPlayer = 3
+
+// Hand é uma mão de truco: as cartas de cada jogador e a vira
+type Hand struct {
+	Rules   Rules
+	Ranking deck.Ranking
+	// Players segue a ordem das cadeiras (players.ordem)
+	Players []uuid.UUID
+	Cards   map[uuid.UUID][]deck.Card
+	Vira    *deck.Card
+	// Mano é a cadeira de quem recebe a primeira carta e abre a mão
+	Mano int
+	// Turn é a cadeira de quem deve jogar agora
+	Turn   int
+	Tricks []Trick
+	Raise  Raise
+	// Winner é o time que venceu a mão, Tie quando ninguém pontua
+	Winner int
+	// Declined indica que a mão acabou porque um time correu do truco
+	Declined bool
+	Done     bool
+
+	Special Special
+	// NoRaise bloqueia pedidos de truco (mão de onze e mão de ferro)
+	NoRaise bool
+	// Blind indica que os jogadores não veem as próprias cartas (mão de ferro)
+	Blind bool
+	// ElevenTeam é o time que decide se joga a mão de onze
+	ElevenTeam       int
+	AwaitingDecision bool
+
+	// Dealt são as cartas recebidas, usadas para contar os pontos do envido
+	Dealt  map[uuid.UUID][]deck.Card
+	Envido Envido
+	// Falta é quanto vale a falta envido nesta mão
+	Falta int
+}
+
+// CardsToDeal é quantas cartas precisam ser compradas do baralho para uma mão
+func CardsToDeal(rules Rules, players int) int {
+	n := players * CardsPerPlayer
+	if rules.Vira {
+		n++
+	}
+	return n
+}
+
+// Deal distribui as cartas compradas uma a uma, começando pelo mano e seguindo a
+// ordem das cadeiras, até cada jogador ter três cartas. A carta seguinte vira
+// a vira nas variantes que usam vira
+func Deal(rules Rules, players []uuid.UUID, mano int, cards []deck.Card) (*Hand, error) {
+	if len(players) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+	if len(cards) < CardsToDeal(rules, len(players)) {
+		return nil, deck.ErrNotEnoughCards
+	}
+
+	hand := &Hand{
+		Rules:   rules,
+		Players: players,
+		Cards:   make(map[uuid.UUID][]deck.Card, len(players)),
+		Mano:    mano % len(players),
+		Winner:  Tie,
+		Raise:   Raise{Caller: Tie, LastRaiser: Tie},
+
+		ElevenTeam: Tie,
+
+		Dealt:  make(map[uuid.UUID][]deck.Card, len(players)),
+		Envido: Envido{Caller: Tie, Winner: Tie},
+		Falta:  rules.Target,
+	}
+	hand.Turn = hand.Mano
+
+	for i := 0; i < len(players)*CardsPerPlayer; i++ {
+		player := players[(hand.Mano+i)%len(players)]
+		hand.Cards[player] = append(hand.Cards[player], cards[i])
+	}
+	for player, dealt := range hand.Cards {
+		hand.Dealt[player] = slices.Clone(dealt)
+	}
+
+	var vira deck.Card
+	if rules.Vira {
+		vira = cards[len(players)*CardsPerPlayer]
+		hand.Vira = &vira
+	}
+	hand.Ranking = rules.Ranking(vira)
+
+	return hand, nil
+}
+
+// Seat retorna a cadeira do jogador na mão
+func (h *Hand) Seat(player uuid.UUID) (int, error) {
+	for seat, p := range h.Players {
+		if p == player {
+			return seat, nil
+		}
+	}
+	return -1, ErrNotInHand
+}
+
+// Team retorna o time de uma cadeira. Os parceiros se alternam na mesa, então
+// cadeiras pares são do time 0 e ímpares do time 1
+func Team(seat int) int {
+	return seat % 2
+}
+
+// CardAt retorna o código da carta na posição index da mão do jogador, usado
+// na mão de ferro em que o jogador escolhe a carta sem vê-la
+func (h *Hand) CardAt(player uuid.UUID, index int) (string, error) {
+	cards := h.Cards[player]
+	if index < 0 || index >= len(cards) {
+		return "", ErrCardNotInHand
+	}
+	return cards[index].Code, nil
+}
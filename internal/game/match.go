@@ -0,0 +1,155 @@
+package game
+
+import "github.com/google/uuid"
+
+// Special identifica as mãos com regras próprias no fim da partida
+type Special string
+
+const (
+	// MaoDeOnze: só um time está a uma mão de vencer. Esse time vê as cartas
+	// do parceiro e decide se joga valendo truco ou corre
+	MaoDeOnze Special = "mao de onze"
+	// MaoDeFerro: os dois times estão a uma mão de vencer e jogam às cegas
+	MaoDeFerro Special = "mao de ferro"
+)
+
+// Match é o placar da partida por time
+type Match struct {
+	Rules Rules  `json:"-"`
+	Score [2]int `json:"score"`
+	// Round é o número da mão atual, salvo em games.round
+	Round    int  `json:"round"`
+	Winner   int  `json:"winner"`
+	Finished bool `json:"finished"`
+}
+
+func NewMatch(rules Rules) *Match {
+	return &Match{Rules: rules, Round: 1, Winner: Tie}
+}
+
+// eleven é a pontuação a partir da qual começa a mão de onze
+func (m *Match) eleven() int {
+	return m.Rules.Target - m.Rules.Ladder[0]
+}
+
+// Special informa se a próxima mão é mão de onze ou de ferro e, na mão de onze,
+// qual time decide
+func (m *Match) Special() (Special, int) {
+	if !m.Rules.MaoDeOnze {
+		return "", Tie
+	}
+
+	onze := [2]bool{m.Score[0] >= m.eleven(), m.Score[1] >= m.eleven()}
+	switch {
+	case onze[0] && onze[1]:
+		return MaoDeFerro, Tie
+	case onze[0]:
+		return MaoDeOnze, 0
+	case onze[1]:
+		return MaoDeOnze, 1
+	}
+	return "", Tie
+}
+
+// Setup aplica à mão recém distribuída as regras da mão de onze e da mão de ferro
+func (m *Match) Setup(h *Hand) {
+	special, team := m.Special()
+	h.Special = special
+	h.Falta = m.Rules.Target - max(m.Score[0], m.Score[1])
+
+	switch special {
+	case MaoDeOnze:
+		h.NoRaise = true
+		h.ElevenTeam = team
+		h.AwaitingDecision = true
+	case MaoDeFerro:
+		h.NoRaise = true
+		h.Blind = true
+	}
+}
+
+// Apply soma os pontos da mão ao placar e avança o contador de mãos
+func (m *Match) Apply(h *Hand) {
+	if m.Finished {
+		return
+	}
+
+	if h.Winner != Tie {
+		if m.Add(h.Winner, h.Value()); m.Finished {
+			return
+		}
+	}
+	m.Round++
+}
+
+// Add soma pontos ao time fora do resultado da mão (envido e flor) e encerra a
+// partida se o time chegou aos pontos
+func (m *Match) Add(team, points int) {
+	if m.Finished {
+		return
+	}
+
+	m.Score[team] += points
+	if m.Score[team] >= m.Rules.Target {
+		m.Winner = team
+		m.Finished = true
+	}
+}
+
+// Forfeit encerra a partida com a derrota do time que abandonou a mesa; o
+// placar fica como estava
+func (m *Match) Forfeit(team int) {
+	if m.Finished {
+		return
+	}
+
+	m.Winner = 1 - team
+	m.Finished = true
+}
+
+// Partners retorna os parceiros do jogador na mão, usados para mostrar as
+// cartas do parceiro na mão de onze
+func (h *Hand) Partners(player uuid.UUID) []uuid.UUID {
+	seat, err := h.Seat(player)
+	if err != nil {
+		return nil
+	}
+
+	var partners []uuid.UUID
+	for s, p := range h.Players {
+		if s != seat && Team(s) == Team(seat) {
+			partners = append(partners, p)
+		}
+	}
+	return partners
+}
+
+// Decide é a decisão do time na mão de onze: jogar vale o truco, correr dá ao
+// adversário o valor da mão simples
+func (h *Hand) Decide(player uuid.UUID, play bool) error {
+	if h.Done {
+		return ErrHandOver
+	}
+	if !h.AwaitingDecision {
+		return ErrNoDecision
+	}
+
+	seat, err := h.Seat(player)
+	if err != nil {
+		return err
+	}
+	if Team(seat) != h.ElevenTeam {
+		return ErrNotElevenTeam
+	}
+
+	h.AwaitingDecision = false
+	if play {
+		h.Raise.Level = 1
+		return nil
+	}
+
+	h.Winner = 1 - h.ElevenTeam
+	h.Declined = true
+	h.Done = true
+	return nil
+}
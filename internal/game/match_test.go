@@ -0,0 +1,186 @@
+package game
+
+import "testing"
+
+func newMatch(t *testing.T, variant Variant, score [2]int) *Match {
+	t.Helper()
+	rules, err := RulesFor(variant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	match := NewMatch(rules)
+	match.Score = score
+	return match
+}
+
+func TestSpecial(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		score   [2]int
+		special Special
+		team    int
+	}{
+		{"paulista normal hand", Paulista, [2]int{10, 10}, "", Tie},
+		{"paulista mão de onze", Paulista, [2]int{11, 4}, MaoDeOnze, 0},
+		{"paulista mão de onze other team", Paulista, [2]int{7, 11}, MaoDeOnze, 1},
+		{"paulista mão de ferro", Paulista, [2]int{11, 11}, MaoDeFerro, Tie},
+		// no mineiro a mão simples vale 2, então a mão de onze começa no 10
+		{"mineiro mão de dez", Mineiro, [2]int{10, 3}, MaoDeOnze, 0},
+		{"mineiro normal hand", Mineiro, [2]int{9, 9}, "", Tie},
+		{"gaucho has no mão de onze", Gaucho, [2]int{23, 23}, "", Tie},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			special, team := newMatch(t, tt.variant, tt.score).Special()
+			if special != tt.special || team != tt.team {
+				t.Errorf("Special() = %q, %d; want %q, %d", special, team, tt.special, tt.team)
+			}
+		})
+	}
+}
+
+func TestMaoDeOnze(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		score   [2]int
+		play    bool
+		// value é quanto vale a mão depois da decisão
+		value int
+		// final é o placar quando o time corre
+		final [2]int
+	}{
+		{"paulista decides to play", Paulista, [2]int{11, 5}, true, 3, [2]int{}},
+		{"paulista runs", Paulista, [2]int{11, 5}, false, 1, [2]int{11, 6}},
+		{"mineiro decides to play", Mineiro, [2]int{4, 10}, true, 4, [2]int{}},
+		{"mineiro runs", Mineiro, [2]int{4, 10}, false, 2, [2]int{6, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := newMatch(t, tt.variant, tt.score)
+			hand := newRaiseHand(t, tt.variant)
+			match.Setup(hand)
+
+			if hand.Special != MaoDeOnze || !hand.AwaitingDecision || !hand.NoRaise {
+				t.Fatalf("hand should wait for the mão de onze decision: %+v", hand)
+			}
+			if _, err := hand.Play(hand.Players[0], "3S"); err != ErrAwaitingDecision {
+				t.Errorf("play before deciding: %v, want %v", err, ErrAwaitingDecision)
+			}
+
+			team := hand.ElevenTeam
+			if err := hand.Decide(hand.Players[1-team], tt.play); err != ErrNotElevenTeam {
+				t.Errorf("opponent deciding: %v, want %v", err, ErrNotElevenTeam)
+			}
+			if err := hand.Decide(hand.Players[team], tt.play); err != nil {
+				t.Fatalf("Decide: %v", err)
+			}
+			if err := hand.Decide(hand.Players[team], tt.play); err == nil {
+				t.Error("deciding twice should fail")
+			}
+
+			if got := hand.Value(); got != tt.value {
+				t.Errorf("Value() = %d, want %d", got, tt.value)
+			}
+			if err := hand.Call(hand.Players[0]); err != ErrRaiseNotAllowed && err != ErrHandOver {
+				t.Errorf("truco in mão de onze: %v", err)
+			}
+
+			if tt.play {
+				if hand.Done {
+					t.Error("playing the mão de onze should keep the hand going")
+				}
+				return
+			}
+
+			if !hand.Done || !hand.Declined || hand.Winner != 1-team {
+				t.Fatalf("running should give the hand to the opponents: %+v", hand)
+			}
+			match.Apply(hand)
+			if match.Score != tt.final {
+				t.Errorf("score = %v, want %v", match.Score, tt.final)
+			}
+		})
+	}
+}
+
+func TestMaoDeFerro(t *testing.T) {
+	match := newMatch(t, Paulista, [2]int{11, 11})
+	hand := newRaiseHand(t, Paulista)
+	match.Setup(hand)
+
+	if hand.Special != MaoDeFerro || !hand.Blind || !hand.NoRaise || hand.AwaitingDecision {
+		t.Fatalf("hand should be a blind mão de ferro: %+v", hand)
+	}
+	if err := hand.Decide(hand.Players[0], true); err != ErrNoDecision {
+		t.Errorf("Decide: %v, want %v", err, ErrNoDecision)
+	}
+
+	// na mão de ferro a carta é escolhida pela posição
+	code, err := hand.CardAt(hand.Players[0], 0)
+	if err != nil || code != "3S" {
+		t.Errorf("CardAt = %q, %v", code, err)
+	}
+	if _, err := hand.CardAt(hand.Players[0], 3); err != ErrCardNotInHand {
+		t.Errorf("CardAt out of range: %v, want %v", err, ErrCardNotInHand)
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		score    [2]int
+		winner   int
+		level    int
+		want     [2]int
+		finished bool
+	}{
+		{"simple hand", [2]int{3, 4}, 0, 0, [2]int{4, 4}, false},
+		{"accepted truco", [2]int{3, 4}, 1, 1, [2]int{3, 7}, false},
+		{"nobody scores", [2]int{3, 4}, Tie, 2, [2]int{3, 4}, false},
+		{"reaching the target", [2]int{10, 4}, 0, 1, [2]int{13, 4}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := newMatch(t, Paulista, tt.score)
+			hand := newRaiseHand(t, Paulista)
+			hand.Winner = tt.winner
+			hand.Raise.Level = tt.level
+			hand.Done = true
+
+			match.Apply(hand)
+			if match.Score != tt.want {
+				t.Errorf("score = %v, want %v", match.Score, tt.want)
+			}
+			if match.Finished != tt.finished {
+				t.Errorf("Finished = %v, want %v", match.Finished, tt.finished)
+			}
+			if tt.finished {
+				if match.Winner != tt.winner || match.Round != 1 {
+					t.Errorf("winner = %d round %d, want %d round 1", match.Winner, match.Round, tt.winner)
+				}
+				return
+			}
+			if match.Round != 2 {
+				t.Errorf("Round = %d, want 2", match.Round)
+			}
+		})
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	match := newMatch(t, Paulista, [2]int{9, 2})
+	match.Forfeit(0)
+	if !match.Finished || match.Winner != 1 || match.Score != [2]int{9, 2} {
+		t.Errorf("forfeit = %+v, want team 1 winning with the score untouched", match)
+	}
+
+	match.Forfeit(1)
+	if match.Winner != 1 {
+		t.Error("a finished match should not change")
+	}
+}
@@ -0,0 +1,129 @@
+package game
+
+import "github.com/google/uuid"
+
+type Answer string
+
+const (
+	Accept  Answer = "accept"
+	Decline Answer = "decline"
+	// ReRaise aceita o pedido e já pede o próximo nível
+	ReRaise Answer = "raise"
+)
+
+// Raise é a máquina de estados das apostas (truco, seis, nove e doze). Level é o
+// índice em Rules.Ladder do valor atual da mão
+type Raise struct {
+	Level int `json:"level"`
+	// Pending indica um pedido aguardando resposta do time adversário
+	Pending bool `json:"pending"`
+	// Caller é o time que fez o pedido pendente
+	Caller int `json:"caller"`
+	// LastRaiser é o time que fez o último aumento aceito; só o outro time pode aumentar de novo
+	LastRaiser int `json:"last_raiser"`
+}
+
+// Value é quanto vale a mão no nível atual. Se o time correu, é o valor
+// anterior ao pedido recusado
+func (h *Hand) Value() int {
+	return h.Rules.Ladder[h.Raise.Level]
+}
+
+// NextCall é o nome do próximo pedido de aumento, vazio se a mão já vale o máximo
+func (h *Hand) NextCall() string {
+	if h.Raise.Level >= len(h.Rules.Calls) {
+		return ""
+	}
+	return h.Rules.Calls[h.Raise.Level]
+}
+
+// Call pede o próximo nível de aposta. Só pode pedir quem está na vez de jogar,
+// e o mesmo time não pode aumentar duas vezes seguidas
+func (h *Hand) Call(player uuid.UUID) error {
+	return h.call(player, true)
+}
+
+func (h *Hand) call(player uuid.UUID, checkTurn bool) error {
+	if h.Done {
+		return ErrHandOver
+	}
+	if h.NoRaise {
+		return ErrRaiseNotAllowed
+	}
+	if h.Raise.Pending {
+		return ErrRaisePending
+	}
+	if h.Envido.Pending {
+		return ErrEnvidoPending
+	}
+
+	seat, err := h.Seat(player)
+	if err != nil {
+		return err
+	}
+	if checkTurn && seat != h.Turn {
+		return ErrNotYourTurn
+	}
+
+	team := Team(seat)
+	if h.Raise.LastRaiser == team {
+		return ErrNotYourRaise
+	}
+	if h.Raise.Level+1 >= len(h.Rules.Ladder) {
+		return ErrMaxRaise
+	}
+
+	h.Raise.Pending = true
+	h.Raise.Caller = team
+	return nil
+}
+
+// Respond responde ao pedido pendente. Correr encerra a mão dando ao time que
+// pediu o valor anterior ao pedido
+func (h *Hand) Respond(player uuid.UUID, answer Answer) error {
+	if h.Done {
+		return ErrHandOver
+	}
+	if !h.Raise.Pending {
+		return ErrNoPendingRaise
+	}
+	// o envido está primeiro: o truco só é respondido depois do envido
+	if h.Envido.Pending {
+		return ErrEnvidoPending
+	}
+
+	seat, err := h.Seat(player)
+	if err != nil {
+		return err
+	}
+	team := Team(seat)
+	if team == h.Raise.Caller {
+		return ErrOwnRaise
+	}
+
+	switch answer {
+	case Accept:
+		h.accept()
+	case Decline:
+		h.Raise.Pending = false
+		h.Winner = h.Raise.Caller
+		h.Declined = true
+		h.Done = true
+	case ReRaise:
+		if h.Raise.Level+2 >= len(h.Rules.Ladder) {
+			return ErrMaxRaise
+		}
+		h.accept()
+		return h.call(player, false)
+	default:
+		return ErrInvalidAnswer
+	}
+
+	return nil
+}
+
+func (h *Hand) accept() {
+	h.Raise.Level++
+	h.Raise.Pending = false
+	h.Raise.LastRaiser = h.Raise.Caller
+}
@@ -0,0 +1,163 @@
+package game
+
+import "testing"
+
+// newRaiseHand é uma mão de dois jogadores em que ninguém jogou ainda
+func newRaiseHand(t *testing.T, variant Variant) *Hand {
+	t.Helper()
+	return newHand(t, variant, "4C", []string{"3S", "KS", "QS"}, []string{"2H", "QH", "6H"})
+}
+
+// raiseTo pede truco na cadeira 0 e responde aumentando reRaises vezes,
+// deixando pendente o pedido para o nível reRaises+1. Retorna o time que pediu
+func raiseTo(t *testing.T, hand *Hand, reRaises int) int {
+	t.Helper()
+	if err := hand.Call(hand.Players[0]); err != nil {
+		t.Fatalf("call: %v", err)
+	}
+	for i := 0; i < reRaises; i++ {
+		answering := 1 - hand.Raise.Caller
+		if err := hand.Respond(hand.Players[answering], ReRaise); err != nil {
+			t.Fatalf("re-raise %d: %v", i+1, err)
+		}
+	}
+	return hand.Raise.Caller
+}
+
+func TestDeclineValue(t *testing.T) {
+	for _, variant := range []Variant{Paulista, Mineiro, Gaucho, Argentino} {
+		rules, _ := RulesFor(variant)
+		// correr do pedido vale o nível anterior ao pedido, do truco até o último aumento
+		for level := 0; level < len(rules.Ladder)-1; level++ {
+			t.Run(string(variant)+" "+rules.Calls[level], func(t *testing.T) {
+				hand := newRaiseHand(t, variant)
+				caller := raiseTo(t, hand, level)
+
+				if err := hand.Respond(hand.Players[1-caller], Decline); err != nil {
+					t.Fatalf("decline: %v", err)
+				}
+				if !hand.Done || !hand.Declined {
+					t.Fatal("declining should end the hand")
+				}
+				if hand.Winner != caller {
+					t.Errorf("winner = %d, want caller %d", hand.Winner, caller)
+				}
+				if got := hand.Value(); got != rules.Ladder[level] {
+					t.Errorf("Value() = %d, want %d", got, rules.Ladder[level])
+				}
+			})
+		}
+	}
+}
+
+func TestAcceptValue(t *testing.T) {
+	tests := []struct {
+		variant Variant
+		want    []int
+	}{
+		{Paulista, []int{3, 6, 9, 12}},
+		{Mineiro, []int{4, 6, 10, 12}},
+		{Gaucho, []int{2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.variant), func(t *testing.T) {
+			for level, want := range tt.want {
+				hand := newRaiseHand(t, tt.variant)
+				caller := raiseTo(t, hand, level)
+				if err := hand.Respond(hand.Players[1-caller], Accept); err != nil {
+					t.Fatalf("accept: %v", err)
+				}
+				if got := hand.Value(); got != want {
+					t.Errorf("accepted level %d: Value() = %d, want %d", level+1, got, want)
+				}
+				if hand.Raise.LastRaiser != caller {
+					t.Errorf("LastRaiser = %d, want %d", hand.Raise.LastRaiser, caller)
+				}
+			}
+		})
+	}
+}
+
+func TestReRaiseLimits(t *testing.T) {
+	for _, variant := range []Variant{Paulista, Mineiro, Gaucho} {
+		t.Run(string(variant), func(t *testing.T) {
+			rules, _ := RulesFor(variant)
+			hand := newRaiseHand(t, variant)
+
+			// o pedido pendente já leva a mão ao valor máximo
+			caller := raiseTo(t, hand, len(rules.Ladder)-2)
+			if err := hand.Respond(hand.Players[1-caller], ReRaise); err != ErrMaxRaise {
+				t.Fatalf("re-raise past the top: %v, want %v", err, ErrMaxRaise)
+			}
+			if !hand.Raise.Pending || hand.Raise.Caller != caller {
+				t.Error("a refused re-raise should keep the pending raise")
+			}
+			if got := hand.NextCall(); got != rules.Calls[len(rules.Calls)-1] {
+				t.Errorf("NextCall() = %q, want %q", got, rules.Calls[len(rules.Calls)-1])
+			}
+
+			if err := hand.Respond(hand.Players[1-caller], Accept); err != nil {
+				t.Fatalf("accept: %v", err)
+			}
+			if got := hand.Value(); got != rules.Ladder[len(rules.Ladder)-1] {
+				t.Errorf("Value() = %d, want the top of the ladder", got)
+			}
+			if got := hand.NextCall(); got != "" {
+				t.Errorf("NextCall() = %q at the top", got)
+			}
+		})
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	t.Run("same team twice", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		hand.Call(hand.Players[0])
+		hand.Respond(hand.Players[1], Accept)
+		if err := hand.Call(hand.Players[0]); err != ErrNotYourRaise {
+			t.Errorf("got %v, want %v", err, ErrNotYourRaise)
+		}
+	})
+
+	t.Run("out of turn", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		if err := hand.Call(hand.Players[1]); err != ErrNotYourTurn {
+			t.Errorf("got %v, want %v", err, ErrNotYourTurn)
+		}
+	})
+
+	t.Run("answer own raise", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		hand.Call(hand.Players[0])
+		if err := hand.Respond(hand.Players[0], Accept); err != ErrOwnRaise {
+			t.Errorf("got %v, want %v", err, ErrOwnRaise)
+		}
+	})
+
+	t.Run("play while pending", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		hand.Call(hand.Players[0])
+		if _, err := hand.Play(hand.Players[0], "3S"); err != ErrRaisePending {
+			t.Errorf("got %v, want %v", err, ErrRaisePending)
+		}
+		if err := hand.Call(hand.Players[0]); err != ErrRaisePending {
+			t.Errorf("got %v, want %v", err, ErrRaisePending)
+		}
+	})
+
+	t.Run("no pending raise", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		if err := hand.Respond(hand.Players[1], Accept); err != ErrNoPendingRaise {
+			t.Errorf("got %v, want %v", err, ErrNoPendingRaise)
+		}
+	})
+
+	t.Run("raises blocked", func(t *testing.T) {
+		hand := newRaiseHand(t, Paulista)
+		hand.NoRaise = true
+		if err := hand.Call(hand.Players[0]); err != ErrRaiseNotAllowed {
+			t.Errorf("got %v, want %v", err, ErrRaiseNotAllowed)
+		}
+	})
+}
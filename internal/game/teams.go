@@ -0,0 +1,69 @@
+package game
+
+import "fmt"
+
+const Teams = 2
+
+// Modos de sala: jogadores por time
+var modes = map[string]int{
+	"1v1": 1,
+	"2v2": 2,
+	"3v3": 3,
+}
+
+// ParseMode converte o modo da sala ("1v1", "2v2" ou "3v3") no tamanho do time.
+// Modo vazio é 1v1
+func ParseMode(mode string) (int, error) {
+	if mode == "" {
+		return 1, nil
+	}
+	size, ok := modes[mode]
+	if !ok {
+		return 0, ErrUnknownMode
+	}
+	return size, nil
+}
+
+func Mode(teamSize int) string {
+	return fmt.Sprintf("%dv%d", teamSize, teamSize)
+}
+
+// Capacity é o número de cadeiras da mesa
+func Capacity(teamSize int) int {
+	return teamSize * Teams
+}
+
+// AssignSeat escolhe a cadeira de um novo jogador. Os parceiros se alternam na
+// mesa, então o time 0 senta nas cadeiras pares e o time 1 nas ímpares. Sem
+// time escolhido o jogador vai para o time com menos jogadores
+func AssignSeat(teamSize int, taken []int, team *int) (int, int, error) {
+	capacity := Capacity(teamSize)
+	if len(taken) >= capacity {
+		return 0, 0, ErrRoomFull
+	}
+
+	occupied := make(map[int]bool, len(taken))
+	var count [Teams]int
+	for _, seat := range taken {
+		occupied[seat] = true
+		count[Team(seat)]++
+	}
+
+	chosen := 0
+	if count[1] < count[0] {
+		chosen = 1
+	}
+	if team != nil {
+		if *team < 0 || *team >= Teams {
+			return 0, 0, ErrUnknownTeam
+		}
+		chosen = *team
+	}
+
+	for seat := chosen; seat < capacity; seat += Teams {
+		if !occupied[seat] {
+			return seat, chosen, nil
+		}
+	}
+	return 0, 0, ErrTeamFull
+}
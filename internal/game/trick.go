@@ -0,0 +1,167 @@
+package game
+
+import (
+	"slices"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/google/uuid"
+)
+
+// Tie indica uma vaza ou mão empatada
+const Tie = -1
+
+type Play struct {
+	Player uuid.UUID `json:"player"`
+	Seat   int       `json:"seat"`
+	Card   deck.Card `json:"card"`
+}
+
+// Trick é uma vaza: uma carta de cada jogador
+type Trick struct {
+	Plays []Play `json:"plays"`
+	// Winner é o time que levou a vaza ou Tie quando cangou
+	Winner int `json:"winner"`
+	// WinnerSeat é a cadeira da carta mais forte; em caso de empate, quem jogou primeiro
+	WinnerSeat int  `json:"winner_seat"`
+	Done       bool `json:"done"`
+}
+
+// Clone copia a vaza com as próprias jogadas, para ser lida fora da trava da sala
+func (t Trick) Clone() Trick {
+	t.Plays = slices.Clone(t.Plays)
+	return t
+}
+
+// Cangou informa se a vaza empatou entre cartas de times diferentes
+func (t Trick) Cangou() bool {
+	return t.Done && t.Winner == Tie
+}
+
+// CurrentTrick retorna a vaza em andamento ou a última vaza jogada
+func (h *Hand) CurrentTrick() *Trick {
+	if len(h.Tricks) == 0 {
+		return nil
+	}
+	return &h.Tricks[len(h.Tricks)-1]
+}
+
+// TricksWon conta as vazas feitas por cada time na mão
+func (h *Hand) TricksWon() [2]int {
+	var wins [2]int
+	for _, trick := range h.Tricks {
+		if trick.Done && trick.Winner != Tie {
+			wins[trick.Winner]++
+		}
+	}
+	return wins
+}
+
+// Play joga a carta do jogador na vaza atual. Quando a vaza se completa ela é
+// resolvida e, se já houver vencedor, a mão é encerrada
+func (h *Hand) Play(player uuid.UUID, code string) (Play, error) {
+	if h.Done {
+		return Play{}, ErrHandOver
+	}
+	if h.Raise.Pending {
+		return Play{}, ErrRaisePending
+	}
+	if h.AwaitingDecision {
+		return Play{}, ErrAwaitingDecision
+	}
+	if h.Envido.Pending {
+		return Play{}, ErrEnvidoPending
+	}
+
+	seat, err := h.Seat(player)
+	if err != nil {
+		return Play{}, err
+	}
+	if seat != h.Turn {
+		return Play{}, ErrNotYourTurn
+	}
+
+	cards := h.Cards[player]
+	i := slices.IndexFunc(cards, func(c deck.Card) bool { return c.Code == code })
+	if i < 0 {
+		return Play{}, ErrCardNotInHand
+	}
+
+	play := Play{Player: player, Seat: seat, Card: cards[i]}
+	h.Cards[player] = slices.Delete(slices.Clone(cards), i, i+1)
+
+	trick := h.CurrentTrick()
+	if trick == nil || trick.Done {
+		h.Tricks = append(h.Tricks, Trick{Winner: Tie})
+		trick = h.CurrentTrick()
+	}
+	trick.Plays = append(trick.Plays, play)
+	h.Turn = (seat + 1) % len(h.Players)
+
+	if len(trick.Plays) == len(h.Players) {
+		h.resolveTrick(trick)
+	}
+
+	return play, nil
+}
+
+func (h *Hand) resolveTrick(trick *Trick) {
+	best := trick.Plays[0]
+	teams := map[int]bool{Team(best.Seat): true}
+
+	for _, play := range trick.Plays[1:] {
+		switch deck.Compare(h.Ranking, play.Card, best.Card) {
+		case 1:
+			best = play
+			teams = map[int]bool{Team(play.Seat): true}
+		case 0:
+			teams[Team(play.Seat)] = true
+		}
+	}
+
+	trick.Done = true
+	trick.WinnerSeat = best.Seat
+	trick.Winner = Team(best.Seat)
+	if len(teams) > 1 {
+		trick.Winner = Tie
+	}
+
+	// quem fez a vaza (ou cangou primeiro) abre a próxima
+	h.Turn = best.Seat
+
+	if winner, done := h.handWinner(); done {
+		h.Winner = winner
+		h.Done = true
+	}
+}
+
+// handWinner aplica as regras de desempate: quem fizer duas vazas vence; se a
+// primeira cangar, vence quem fizer a próxima; se outra vaza cangar, vence
+// quem fez a primeira; se as três cangarem ninguém pontua
+func (h *Hand) handWinner() (int, bool) {
+	var wins [2]int
+	for _, trick := range h.Tricks {
+		if trick.Winner != Tie {
+			wins[trick.Winner]++
+		}
+	}
+	for team, won := range wins {
+		if won >= 2 {
+			return team, true
+		}
+	}
+
+	first := h.Tricks[0]
+	for _, trick := range h.Tricks[1:] {
+		if first.Winner == Tie && trick.Winner != Tie {
+			return trick.Winner, true
+		}
+		if first.Winner != Tie && trick.Winner == Tie {
+			return first.Winner, true
+		}
+	}
+
+	if len(h.Tricks) == CardsPerPlayer {
+		return Tie, true
+	}
+	return Tie, false
+}
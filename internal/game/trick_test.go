@@ -0,0 +1,217 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+	"github.com/google/uuid"
+)
+
+// newHand distribui as cartas de cada cadeira com o mano na cadeira 0. vira só
+// é usada nas variantes que viram carta
+func newHand(t *testing.T, variant Variant, vira string, seats ...[]string) *Hand {
+	t.Helper()
+
+	rules, err := RulesFor(variant)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	players := make([]uuid.UUID, len(seats))
+	for i := range players {
+		players[i] = uuid.New()
+	}
+
+	var cards []deck.Card
+	for i := 0; i < CardsPerPlayer; i++ {
+		for _, seat := range seats {
+			cards = append(cards, parseCard(t, seat[i]))
+		}
+	}
+	if rules.Vira {
+		cards = append(cards, parseCard(t, vira))
+	}
+
+	hand, err := Deal(rules, players, 0, cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hand
+}
+
+func parseCard(t *testing.T, code string) deck.Card {
+	t.Helper()
+	card, err := deck.ParseCard(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return card
+}
+
+// playTrick joga uma vaza seguindo a vez da mesa; cards é a carta de cada cadeira
+func playTrick(t *testing.T, hand *Hand, cards ...string) {
+	t.Helper()
+	for range hand.Players {
+		seat := hand.Turn
+		if _, err := hand.Play(hand.Players[seat], cards[seat]); err != nil {
+			t.Fatalf("seat %d playing %s: %v", seat, cards[seat], err)
+		}
+	}
+}
+
+func TestHandWinner(t *testing.T) {
+	// vira 4♣: as manilhas são os 5
+	tests := []struct {
+		name   string
+		seats  [][]string
+		tricks [][]string
+		// want são os vencedores de cada vaza jogada e winner o da mão
+		want   []int
+		winner int
+	}{
+		{
+			name:   "two tricks win",
+			seats:  [][]string{{"3S", "KS", "QS"}, {"2H", "QH", "6H"}},
+			tricks: [][]string{{"3S", "2H"}, {"KS", "QH"}},
+			want:   []int{0, 0},
+			winner: 0,
+		},
+		{
+			name:   "first trick wins ties",
+			seats:  [][]string{{"3S", "KS", "QS"}, {"2H", "KH", "6H"}},
+			tricks: [][]string{{"3S", "2H"}, {"KS", "KH"}},
+			want:   []int{0, Tie},
+			winner: 0,
+		},
+		{
+			name:   "first trick wins a tie in the third",
+			seats:  [][]string{{"3S", "QS", "7S"}, {"2H", "KH", "7H"}},
+			tricks: [][]string{{"3S", "2H"}, {"QS", "KH"}, {"7S", "7H"}},
+			want:   []int{0, 1, Tie},
+			winner: 0,
+		},
+		{
+			name:   "cangou first, next trick decides",
+			seats:  [][]string{{"3S", "QS", "7S"}, {"3H", "KH", "7H"}},
+			tricks: [][]string{{"3S", "3H"}, {"QS", "KH"}},
+			want:   []int{Tie, 1},
+			winner: 1,
+		},
+		{
+			name:   "two cangadas, third decides",
+			seats:  [][]string{{"3S", "2S", "AS"}, {"3H", "2H", "KH"}},
+			tricks: [][]string{{"3S", "3H"}, {"2S", "2H"}, {"AS", "KH"}},
+			want:   []int{Tie, Tie, 0},
+			winner: 0,
+		},
+		{
+			name:   "three cangadas, nobody scores",
+			seats:  [][]string{{"3S", "2S", "AS"}, {"3H", "2H", "AH"}},
+			tricks: [][]string{{"3S", "3H"}, {"2S", "2H"}, {"AS", "AH"}},
+			want:   []int{Tie, Tie, Tie},
+			winner: Tie,
+		},
+		{
+			name:   "manilha beats 3",
+			seats:  [][]string{{"3S", "7S", "2S"}, {"5D", "6H", "KH"}},
+			tricks: [][]string{{"3S", "5D"}, {"7S", "6H"}, {"2S", "KH"}},
+			want:   []int{1, 0, 0},
+			winner: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := newHand(t, Paulista, "4C", tt.seats...)
+			for _, trick := range tt.tricks {
+				playTrick(t, hand, trick...)
+			}
+
+			if len(hand.Tricks) != len(tt.want) {
+				t.Fatalf("played %d tricks, want %d", len(hand.Tricks), len(tt.want))
+			}
+			for i, trick := range hand.Tricks {
+				if trick.Winner != tt.want[i] {
+					t.Errorf("trick %d winner = %d, want %d", i+1, trick.Winner, tt.want[i])
+				}
+				if trick.Cangou() != (tt.want[i] == Tie) {
+					t.Errorf("trick %d Cangou() = %v", i+1, trick.Cangou())
+				}
+			}
+			if !hand.Done {
+				t.Fatal("hand should be over")
+			}
+			if hand.Winner != tt.winner {
+				t.Errorf("hand winner = %d, want %d", hand.Winner, tt.winner)
+			}
+		})
+	}
+}
+
+func TestResolveTrick(t *testing.T) {
+	tests := []struct {
+		name       string
+		cards      []string
+		winner     int
+		winnerSeat int
+	}{
+		{"strongest card", []string{"KS", "3H", "QD", "2C"}, 1, 1},
+		{"tie between partners is not a cangada", []string{"3S", "2H", "3H", "KD"}, 0, 0},
+		{"tie between opponents cangou", []string{"2S", "3H", "3D", "QH"}, Tie, 1},
+		{"zap beats the other manilhas", []string{"5D", "5H", "5S", "5C"}, 1, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seats := make([][]string, len(tt.cards))
+			for i, card := range tt.cards {
+				// o resto da mão não importa, só precisa ser de cartas diferentes
+				seats[i] = []string{card, []string{"4S", "4H", "4D", "6C"}[i], []string{"6S", "6H", "6D", "7C"}[i]}
+			}
+			hand := newHand(t, Paulista, "4C", seats...)
+			playTrick(t, hand, tt.cards...)
+
+			trick := hand.Tricks[0]
+			if trick.Winner != tt.winner || trick.WinnerSeat != tt.winnerSeat {
+				t.Errorf("winner = %d seat %d, want %d seat %d", trick.Winner, trick.WinnerSeat, tt.winner, tt.winnerSeat)
+			}
+			if hand.Turn != tt.winnerSeat {
+				t.Errorf("next turn = %d, want %d", hand.Turn, tt.winnerSeat)
+			}
+		})
+	}
+}
+
+func TestPlayErrors(t *testing.T) {
+	hand := newHand(t, Paulista, "4C", []string{"3S", "KS", "QS"}, []string{"2H", "QH", "6H"})
+
+	if _, err := hand.Play(hand.Players[1], "2H"); err != ErrNotYourTurn {
+		t.Errorf("playing out of turn: %v, want %v", err, ErrNotYourTurn)
+	}
+	if _, err := hand.Play(hand.Players[0], "2H"); err != ErrCardNotInHand {
+		t.Errorf("playing someone else's card: %v, want %v", err, ErrCardNotInHand)
+	}
+	if _, err := hand.Play(uuid.New(), "3S"); err != ErrNotInHand {
+		t.Errorf("playing from outside the hand: %v, want %v", err, ErrNotInHand)
+	}
+}
+
+func TestTrickClone(t *testing.T) {
+	hand := newHand(t, Paulista, "4C", []string{"3S", "KS", "QS"}, []string{"2H", "QH", "6H"})
+	if _, err := hand.Play(hand.Players[0], "3S"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := hand.CurrentTrick().Clone()
+	if _, err := hand.Play(hand.Players[1], "2H"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot.Plays[0].Card.Code = "XX"
+	if hand.Tricks[0].Plays[0].Card.Code != "3S" {
+		t.Error("the clone shares plays with the hand")
+	}
+	if snapshot.Done || len(snapshot.Plays) != 1 {
+		t.Errorf("the clone changed with the hand: %+v", snapshot)
+	}
+}
@@ -0,0 +1,102 @@
+package game
+
+import "github.com/JoaoRafa19/truco-backend-go/internal/deck"
+
+type Variant string
+
+const (
+	Paulista  Variant = "paulista"
+	Mineiro   Variant = "mineiro"
+	Gaucho    Variant = "gaucho"
+	Argentino Variant = "argentino"
+)
+
+// Rules reúne tudo que muda entre as variantes do truco
+type Rules struct {
+	Variant Variant
+	// Target é a pontuação que encerra a partida
+	Target int
+	// Ladder é o valor da mão em cada nível de aposta; Ladder[0] é a mão sem truco
+	Ladder []int
+	// Calls são os nomes dos pedidos de aumento, Calls[i] leva a mão para Ladder[i+1]
+	Calls []string
+	// Values são os valores das cartas que entram no baralho da variante
+	Values []string
+	// Vira indica se uma carta é virada para definir as manilhas
+	Vira bool
+	// Envido habilita envido e flor
+	Envido bool
+	// MaoDeOnze habilita a mão de onze e a mão de ferro
+	MaoDeOnze bool
+}
+
+// cleanDeck é o baralho limpo, sem 8, 9, 10 e coringas: as mesmas 40 cartas do
+// baralho espanhol. A ordem faz parte do embaralhamento, mudar ela muda as
+// mãos refeitas a partir das seeds salvas
+var cleanDeck = []string{"A", "2", "3", "4", "5", "6", "7", "Q", "J", "K"}
+
+var rules = map[Variant]Rules{
+	Paulista: {
+		Variant: Paulista,
+		Target:  12,
+		Ladder:  []int{1, 3, 6, 9, 12},
+		Calls:   []string{"truco", "seis", "nove", "doze"},
+		Values:  cleanDeck,
+		Vira:    true,
+
+		MaoDeOnze: true,
+	},
+	Mineiro: {
+		Variant: Mineiro,
+		Target:  12,
+		Ladder:  []int{2, 4, 6, 10, 12},
+		Calls:   []string{"truco", "seis", "dez", "doze"},
+		Values:  cleanDeck,
+
+		MaoDeOnze: true,
+	},
+	Gaucho: {
+		Variant: Gaucho,
+		Target:  24,
+		Ladder:  []int{1, 2, 3, 4},
+		Calls:   []string{"truco", "retruco", "vale quatro"},
+		Values:  cleanDeck,
+		Envido:  true,
+	},
+	Argentino: {
+		Variant: Argentino,
+		Target:  30,
+		Ladder:  []int{1, 2, 3, 4},
+		Calls:   []string{"truco", "retruco", "vale cuatro"},
+		Values:  cleanDeck,
+		Envido:  true,
+	},
+}
+
+// RulesFor retorna as regras da variante; variante vazia é truco paulista
+func RulesFor(v Variant) (Rules, error) {
+	if v == "" {
+		v = Paulista
+	}
+	r, ok := rules[v]
+	if !ok {
+		return Rules{}, ErrUnknownVariant
+	}
+	return r, nil
+}
+
+// Cards é a composição do baralho da variante, montada a partir de Values
+func (r Rules) Cards() []deck.Card {
+	return deck.CardsOf(r.Values)
+}
+
+// Ranking retorna a força das cartas da mão. A vira só é usada nas variantes que viram carta
+func (r Rules) Ranking(vira deck.Card) deck.Ranking {
+	switch r.Variant {
+	case Mineiro:
+		return deck.MineiroRanking
+	case Gaucho, Argentino:
+		return deck.SpanishRanking
+	}
+	return deck.NewViraRanking(vira)
+}
@@ -0,0 +1,49 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JoaoRafa19/truco-backend-go/internal/deck"
+)
+
+func TestVariantCards(t *testing.T) {
+	for _, variant := range []Variant{Paulista, Mineiro, Gaucho, Argentino} {
+		t.Run(string(variant), func(t *testing.T) {
+			rules, err := RulesFor(variant)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cards := rules.Cards()
+			if len(cards) != len(rules.Values)*4 {
+				t.Fatalf("deck has %d cards, want %d", len(cards), len(rules.Values)*4)
+			}
+
+			seen := make(map[string]bool, len(cards))
+			for _, card := range cards {
+				if seen[card.Code] {
+					t.Errorf("card %s twice", card.Code)
+				}
+				seen[card.Code] = true
+				if !slices.ContainsFunc(rules.Values, func(v string) bool { return card.Code[:1] == v }) {
+					t.Errorf("card %s is not in the variant", card.Code)
+				}
+			}
+
+			// as seeds salvas só refazem as mãos se a ordem do baralho não mudar
+			if !slices.Equal(cards, deck.TrucoCards()) {
+				t.Error("composition order changed")
+			}
+		})
+	}
+}
+
+func TestCallsMatchLadder(t *testing.T) {
+	for _, variant := range []Variant{Paulista, Mineiro, Gaucho, Argentino} {
+		rules, _ := RulesFor(variant)
+		if len(rules.Calls) != len(rules.Ladder)-1 {
+			t.Errorf("%s: %d calls for %d levels", variant, len(rules.Calls), len(rules.Ladder))
+		}
+	}
+}
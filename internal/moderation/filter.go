@@ -0,0 +1,195 @@
+package moderation
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+//go:embed lists/*.txt
+var lists embed.FS
+
+var ErrUnknownLanguage = errors.New("no word list for language")
+
+// Filter troca por asteriscos as palavras proibidas de uma mensagem. Acentos e
+// maiúsculas são ignorados na comparação e as entradas podem ter mais de uma
+// palavra, como "hijo de puta"
+type Filter struct {
+	words map[string]bool
+	// maxWords é o número de palavras da maior entrada
+	maxWords int
+}
+
+func NewFilter(words []string) *Filter {
+	f := &Filter{words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		tokens := tokenize(word)
+		if len(tokens) == 0 {
+			continue
+		}
+		f.words[joinTokens(tokens)] = true
+		f.maxWords = max(f.maxWords, len(tokens))
+	}
+	return f
+}
+
+// Clean retorna a mensagem com as palavras proibidas mascaradas e se alguma
+// foi encontrada
+func (f *Filter) Clean(message string) (string, bool) {
+	if f == nil || len(f.words) == 0 {
+		return message, false
+	}
+
+	tokens := tokenize(message)
+	runes := []rune(message)
+	masked := make([]rune, 0, len(runes))
+	found := false
+	last := 0
+
+	for i := 0; i < len(tokens); {
+		n := f.match(tokens[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+
+		found = true
+		masked = append(masked, runes[last:tokens[i].start]...)
+		for _, r := range runes[tokens[i].start:tokens[i+n-1].end] {
+			switch {
+			case isMark(r):
+				// o acento decomposto sairia em cima do asterisco
+			case isWordRune(r):
+				masked = append(masked, '*')
+			default:
+				masked = append(masked, r)
+			}
+		}
+		last = tokens[i+n-1].end
+		i += n
+	}
+
+	if !found {
+		return message, false
+	}
+	masked = append(masked, runes[last:]...)
+	return string(masked), true
+}
+
+// match retorna quantas palavras do início de tokens formam a maior entrada
+// proibida, ou zero
+func (f *Filter) match(tokens []token) int {
+	for n := min(f.maxWords, len(tokens)); n > 0; n-- {
+		if f.words[joinTokens(tokens[:n])] {
+			return n
+		}
+	}
+	return 0
+}
+
+// LoadLanguages lê as listas de palavras embutidas, como "pt" e "es"
+func LoadLanguages(languages ...string) ([]string, error) {
+	var words []string
+	for _, language := range languages {
+		file, err := lists.Open("lists/" + language + ".txt")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownLanguage, language)
+		}
+
+		list, err := ReadWords(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, list...)
+	}
+	return words, nil
+}
+
+// ReadWords lê uma palavra por linha, ignorando linhas vazias e comentários
+// começando com #
+func ReadWords(r io.Reader) ([]string, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// token é uma palavra normalizada e a posição dela, em runas, no texto original
+type token struct {
+	word       string
+	start, end int
+}
+
+func tokenize(text string) []token {
+	var tokens []token
+	var word strings.Builder
+	start := -1
+
+	runes := []rune(text)
+	for i, r := range runes {
+		// acentos decompostos, como "e" seguido de U+0301, continuam a palavra
+		if isMark(r) && start >= 0 {
+			continue
+		}
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			word.WriteRune(fold(unicode.ToLower(r)))
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{word: word.String(), start: start, end: i})
+			word.Reset()
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{word: word.String(), start: start, end: len(runes)})
+	}
+	return tokens
+}
+
+func joinTokens(tokens []token) string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.word
+	}
+	return strings.Join(words, " ")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isMark(r rune) bool {
+	return unicode.Is(unicode.Mn, r)
+}
+
+var accents = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// fold tira o acento de uma letra minúscula
+func fold(r rune) rune {
+	if base, ok := accents[r]; ok {
+		return base
+	}
+	return r
+}
@@ -0,0 +1,83 @@
+package moderation
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	f := NewFilter([]string{"merda", "puta", "hijo de puta", "coño", "desgraçado", "cu", "filho da"})
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+		found   bool
+	}{
+		{"clean message", "truco, seis!", "truco, seis!", false},
+		{"plain word", "que merda", "que *****", true},
+		{"uppercase", "QUE MERDA", "QUE *****", true},
+		{"punctuation kept", "merda!!", "*****!!", true},
+
+		// acentos
+		{"accent in message", "mérda", "*****", true},
+		{"accent in list", "coño", "****", true},
+		{"accent missing in message", "cono", "****", true},
+		{"cedilla", "Desgracado", "**********", true},
+		{"uppercase accent", "DESGRAÇADO", "**********", true},
+		{"decomposed accent", "me\u0301rda", "*****", true},
+
+		// runas de mais de um byte junto das palavras mascaradas
+		{"emoji after word", "merda😡", "*****😡", true},
+		{"emoji before word", "😡merda 😡", "😡***** 😡", true},
+		{"accented neighbours", "ação merda ação", "ação ***** ação", true},
+		{"cjk neighbours", "日本 merda 日本", "日本 ***** 日本", true},
+
+		// entradas com mais de uma palavra e sobrepostas
+		{"multi-word entry", "hijo de puta", "**** ** ****", true},
+		{"multi-word spacing kept", "hijo  de\tputa", "****  **\t****", true},
+		{"multi-word punctuation", "hijo, de puta", "****, ** ****", true},
+		{"longest entry wins", "eres hijo de puta", "eres **** ** ****", true},
+		{"partial multi-word", "hijo de alguien", "hijo de alguien", false},
+		{"overlapping entries", "filho da puta", "***** ** ****", true},
+		{"repeated word", "merda merda", "***** *****", true},
+
+		// limites de palavra
+		{"word inside another", "computador", "computador", false},
+		{"prefix", "merdinha", "merdinha", false},
+		{"short word inside another", "cuidado com o cu", "cuidado com o **", true},
+		{"digits are word runes", "merda1", "merda1", false},
+		{"split by underscore", "merda_total", "*****_total", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := f.Clean(tt.message)
+			if got != tt.want || found != tt.found {
+				t.Errorf("Clean(%q) = %q, %v, want %q, %v", tt.message, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestCleanWithoutWords(t *testing.T) {
+	var f *Filter
+	if got, found := f.Clean("merda"); got != "merda" || found {
+		t.Errorf("nil filter: Clean() = %q, %v", got, found)
+	}
+
+	f = NewFilter([]string{"", "  ", "!!"})
+	if got, found := f.Clean("merda"); got != "merda" || found {
+		t.Errorf("empty filter: Clean() = %q, %v", got, found)
+	}
+}
+
+func TestLoadLanguages(t *testing.T) {
+	words, err := LoadLanguages("pt", "es")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := NewFilter(words).Clean("que MIERDA, desgraçado"); got != "que ******, **********" {
+		t.Errorf("Clean() = %q", got)
+	}
+
+	if _, err := LoadLanguages("xx"); err == nil {
+		t.Error("unknown language loaded")
+	}
+}
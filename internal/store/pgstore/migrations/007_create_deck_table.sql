@@ -0,0 +1,12 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS decks (
+    "id"            VARCHAR(255)    PRIMARY KEY NOT NULL,
+    "shuffled"      BOOLEAN                     NOT NULL DEFAULT false,
+    "cards"         JSONB                       NOT NULL DEFAULT '[]'::jsonb,
+    "created_at"    TIMESTAMP                   NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS decks;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
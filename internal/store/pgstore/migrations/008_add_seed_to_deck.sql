@@ -0,0 +1,9 @@
+-- Write your migrate up statements here
+ALTER TABLE decks ADD seed BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE games ADD seed BIGINT NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+ALTER TABLE games DROP COLUMN seed;
+ALTER TABLE decks DROP COLUMN seed;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
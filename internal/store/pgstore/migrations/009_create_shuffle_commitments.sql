@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS shuffle_commitments (
+    "id"            uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    "room_id"       uuid                    NOT NULL,
+    "round"         INTEGER                 NOT NULL,
+    "hash"          VARCHAR(64)             NOT NULL,
+    "server_seed"   VARCHAR(64)             NOT NULL,
+    "client_seeds"  JSONB                   NOT NULL DEFAULT '[]'::jsonb,
+    "revealed"      BOOLEAN                 NOT NULL DEFAULT false,
+    "created_at"    TIMESTAMP               NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (room_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS shuffle_commitments;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,13 @@
+-- Write your migrate up statements here
+DROP TYPE IF EXISTS variant;
+CREATE TYPE variant AS ENUM ('paulista', 'mineiro', 'gaucho', 'argentino');
+
+ALTER TABLE games ADD variant variant NOT NULL DEFAULT 'paulista'::variant;
+ALTER TABLE decks ADD composition JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+---- create above / drop below ----
+ALTER TABLE decks DROP COLUMN composition;
+ALTER TABLE games DROP COLUMN variant;
+DROP TYPE IF EXISTS variant;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,21 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS player_hands (
+    "id"            uuid        PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    "room_id"       uuid                    NOT NULL,
+    "player_id"     uuid                    NOT NULL,
+    "round"         INTEGER                 NOT NULL,
+    "cards"         JSONB                   NOT NULL DEFAULT '[]'::jsonb,
+    "created_at"    TIMESTAMP               NOT NULL DEFAULT now(),
+
+    UNIQUE (player_id, round),
+    FOREIGN KEY (room_id)   REFERENCES games(id)    ON DELETE CASCADE,
+    FOREIGN KEY (player_id) REFERENCES players(id)  ON DELETE CASCADE
+);
+
+ALTER TABLE games ADD vira JSONB;
+
+---- create above / drop below ----
+ALTER TABLE games DROP COLUMN vira;
+DROP TABLE IF EXISTS player_hands;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
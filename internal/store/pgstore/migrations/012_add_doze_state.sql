@@ -0,0 +1,4 @@
+-- Write your migrate up statements here
+ALTER TYPE state ADD VALUE IF NOT EXISTS 'doze';
+
+-- Valores de enum não podem ser removidos, migração irreversível
@@ -0,0 +1,43 @@
+-- Write your migrate up statements here
+ALTER TABLE games ADD team_size INTEGER NOT NULL DEFAULT 1 CHECK (team_size BETWEEN 1 AND 3);
+ALTER TABLE players ADD team INTEGER NOT NULL DEFAULT -1;
+
+-- salas antigas podem ter dois jogadores com a mesma ordem: um deles fica com
+-- a cadeira e os outros ficam sem cadeira, senão o índice não é criado
+UPDATE players SET ordem = -1
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY room_id, ordem ORDER BY id) AS n
+        FROM players
+        WHERE ordem >= 0
+    ) seats
+    WHERE n > 1
+);
+
+-- salas antigas não tinham modo: a mesa cresce até caber os jogadores
+-- sentados, até 3v3, e quem ainda ficar fora das cadeiras perde a cadeira.
+-- Sem isso a sala nunca fica com a mesa exata e não pode começar
+UPDATE games SET team_size = LEAST(3, (seated.last_seat + 2) / 2)
+FROM (
+    SELECT room_id, MAX(ordem) AS last_seat
+    FROM players
+    WHERE ordem >= 0
+    GROUP BY room_id
+) seated
+WHERE seated.room_id = games.id;
+
+UPDATE players SET ordem = -1
+FROM games
+WHERE players.room_id = games.id AND players.ordem >= games.team_size * 2;
+
+-- os parceiros se alternam na mesa
+UPDATE players SET team = ordem % 2 WHERE ordem >= 0;
+
+CREATE UNIQUE INDEX idx_players_room_seat ON players (room_id, ordem) WHERE ordem >= 0;
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS idx_players_room_seat;
+ALTER TABLE players DROP COLUMN team;
+ALTER TABLE games DROP COLUMN team_size;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,7 @@
+-- Write your migrate up statements here
+ALTER TABLE games ADD turn_seconds INTEGER NOT NULL DEFAULT 30 CHECK (turn_seconds >= 0);
+
+---- create above / drop below ----
+ALTER TABLE games DROP COLUMN turn_seconds;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
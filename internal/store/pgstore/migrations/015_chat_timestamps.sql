@@ -0,0 +1,29 @@
+-- Write your migrate up statements here
+ALTER TABLE chat_messages
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at::timestamp,
+    ALTER COLUMN created_at SET DEFAULT now();
+
+-- o histórico é da sala: quem sai do lobby é apagado de players, mas as
+-- mensagens ficam, só sem autor. Sem isso o DELETE em players falha
+ALTER TABLE chat_messages
+    ALTER COLUMN player DROP NOT NULL,
+    DROP CONSTRAINT IF EXISTS chat_messages_player_fkey,
+    ADD CONSTRAINT chat_messages_player_fkey FOREIGN KEY (player) REFERENCES players(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_chat_messages_room_created ON chat_messages (room_id, created_at, id);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS idx_chat_messages_room_created;
+
+-- as mensagens sem autor não cabem na coluna NOT NULL antiga
+DELETE FROM chat_messages WHERE player IS NULL;
+ALTER TABLE chat_messages
+    DROP CONSTRAINT IF EXISTS chat_messages_player_fkey,
+    ADD CONSTRAINT chat_messages_player_fkey FOREIGN KEY (player) REFERENCES players(id),
+    ALTER COLUMN player SET NOT NULL;
+
+ALTER TABLE chat_messages
+    ALTER COLUMN created_at TYPE DATE USING created_at::date,
+    ALTER COLUMN created_at SET DEFAULT now();
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+DROP TYPE IF EXISTS chat_channel;
+CREATE TYPE chat_channel AS ENUM ('room', 'team');
+
+-- team é o time de quem enviou; mensagens do canal team só aparecem para esse time
+ALTER TABLE chat_messages
+    ADD channel chat_channel NOT NULL DEFAULT 'room'::chat_channel,
+    ADD team    INTEGER      NOT NULL DEFAULT -1,
+    ADD CONSTRAINT chat_messages_team_channel CHECK (channel = 'room' OR team >= 0);
+
+---- create above / drop below ----
+ALTER TABLE chat_messages
+    DROP CONSTRAINT IF EXISTS chat_messages_team_channel,
+    DROP COLUMN team,
+    DROP COLUMN channel;
+DROP TYPE IF EXISTS chat_channel;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
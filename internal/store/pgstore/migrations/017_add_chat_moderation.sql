@@ -0,0 +1,33 @@
+-- Write your migrate up statements here
+-- host_id é o primeiro jogador a entrar na sala
+ALTER TABLE games ADD host_id uuid REFERENCES players(id) ON DELETE SET NULL;
+
+UPDATE games SET host_id = (
+    SELECT id FROM players
+    WHERE players.room_id = games.id AND ordem >= 0
+    ORDER BY ordem
+    LIMIT 1
+);
+
+-- as denúncias guardam uma cópia da mensagem e não dependem da sala, que é
+-- apagada quando o jogo termina
+CREATE TABLE IF NOT EXISTS chat_reports (
+    "id"                uuid            PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    "message_id"        uuid,
+    "room_id"           uuid                        NOT NULL,
+    "reporter"          uuid                        NOT NULL,
+    "reported_player"   uuid                        NOT NULL,
+    "message"           VARCHAR                     NOT NULL,
+    "reason"            VARCHAR(255)                NOT NULL DEFAULT '',
+    "reviewed"          BOOLEAN                     NOT NULL DEFAULT false,
+    "created_at"        TIMESTAMP                   NOT NULL DEFAULT now(),
+
+    FOREIGN KEY (message_id) REFERENCES chat_messages(id) ON DELETE SET NULL,
+    UNIQUE (message_id, reporter)
+);
+
+---- create above / drop below ----
+DROP TABLE IF EXISTS chat_reports;
+ALTER TABLE games DROP COLUMN host_id;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,25 @@
+-- Write your migrate up statements here
+DROP TYPE IF EXISTS lobby_status;
+CREATE TYPE lobby_status AS ENUM ('waiting', 'ready_check', 'in_progress', 'finished');
+
+ALTER TABLE games
+    ADD status      lobby_status NOT NULL DEFAULT 'waiting'::lobby_status,
+    ADD archived_at TIMESTAMP;
+
+ALTER TABLE players ADD ready BOOLEAN NOT NULL DEFAULT false;
+
+-- partidas já encerradas ficam arquivadas
+UPDATE games SET status = 'finished', archived_at = now()
+WHERE (result->>'finished')::boolean;
+
+UPDATE games SET status = 'in_progress'
+WHERE status = 'waiting' AND result IS NOT NULL;
+
+---- create above / drop below ----
+ALTER TABLE players DROP COLUMN ready;
+ALTER TABLE games
+    DROP COLUMN archived_at,
+    DROP COLUMN status;
+DROP TYPE IF EXISTS lobby_status;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
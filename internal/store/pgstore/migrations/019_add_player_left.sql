@@ -0,0 +1,9 @@
+-- Write your migrate up statements here
+-- quem sai de uma sala que já saiu do lobby fica no banco com left_at, assim o
+-- chat e as mãos da partida continuam no arquivo
+ALTER TABLE players ADD left_at TIMESTAMP;
+
+---- create above / drop below ----
+ALTER TABLE players DROP COLUMN left_at;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+-- cada mão guarda a seed usada no embaralhamento junto do commitment; games.seed
+-- fica só com a seed do baralho criado com a sala
+ALTER TABLE shuffle_commitments ADD seed BIGINT NOT NULL DEFAULT 0;
+
+-- antes do commitment ser reaproveitado uma rodada podia ter mais de um
+-- pendente; fica o mais recente, que é o que a mão usou
+DELETE FROM shuffle_commitments c
+USING shuffle_commitments newer
+WHERE c.room_id = newer.room_id
+  AND c.round = newer.round
+  AND c.created_at < newer.created_at;
+
+CREATE UNIQUE INDEX shuffle_commitments_room_round ON shuffle_commitments (room_id, round);
+
+---- create above / drop below ----
+DROP INDEX IF EXISTS shuffle_commitments_room_round;
+ALTER TABLE shuffle_commitments DROP COLUMN seed;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
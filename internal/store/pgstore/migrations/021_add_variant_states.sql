@@ -0,0 +1,8 @@
+-- Write your migrate up statements here
+-- games.state guarda o nome do pedido de cada variante
+ALTER TYPE state ADD VALUE IF NOT EXISTS 'dez';
+ALTER TYPE state ADD VALUE IF NOT EXISTS 'retruco';
+ALTER TYPE state ADD VALUE IF NOT EXISTS 'vale quatro';
+ALTER TYPE state ADD VALUE IF NOT EXISTS 'vale cuatro';
+
+-- Valores de enum não podem ser removidos, migração irreversível
@@ -12,13 +12,104 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ChatChannel string
+
+const (
+	ChatChannelRoom ChatChannel = "room"
+	ChatChannelTeam ChatChannel = "team"
+)
+
+func (e *ChatChannel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ChatChannel(s)
+	case string:
+		*e = ChatChannel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ChatChannel: %T", src)
+	}
+	return nil
+}
+
+type NullChatChannel struct {
+	ChatChannel ChatChannel
+	Valid       bool // Valid is true if ChatChannel is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullChatChannel) Scan(value interface{}) error {
+	if value == nil {
+		ns.ChatChannel, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ChatChannel.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullChatChannel) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ChatChannel), nil
+}
+
+type LobbyStatus string
+
+const (
+	LobbyStatusWaiting    LobbyStatus = "waiting"
+	LobbyStatusReadyCheck LobbyStatus = "ready_check"
+	LobbyStatusInProgress LobbyStatus = "in_progress"
+	LobbyStatusFinished   LobbyStatus = "finished"
+)
+
+func (e *LobbyStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LobbyStatus(s)
+	case string:
+		*e = LobbyStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LobbyStatus: %T", src)
+	}
+	return nil
+}
+
+type NullLobbyStatus struct {
+	LobbyStatus LobbyStatus
+	Valid       bool // Valid is true if LobbyStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLobbyStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.LobbyStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LobbyStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLobbyStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LobbyStatus), nil
+}
+
 type State string
 
 const (
-	StateNormal State = "normal"
-	StateTruco  State = "truco"
-	StateSeis   State = "seis"
-	StateNove   State = "nove"
+	StateNormal     State = "normal"
+	StateTruco      State = "truco"
+	StateSeis       State = "seis"
+	StateNove       State = "nove"
+	StateDoze       State = "doze"
+	StateDez        State = "dez"
+	StateRetruco    State = "retruco"
+	StateValeQuatro State = "vale quatro"
+	StateValeCuatro State = "vale cuatro"
 )
 
 func (e *State) Scan(src interface{}) error {
@@ -56,21 +147,96 @@ func (ns NullState) Value() (driver.Value, error) {
 	return string(ns.State), nil
 }
 
+type Variant string
+
+const (
+	VariantPaulista  Variant = "paulista"
+	VariantMineiro   Variant = "mineiro"
+	VariantGaucho    Variant = "gaucho"
+	VariantArgentino Variant = "argentino"
+)
+
+func (e *Variant) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = Variant(s)
+	case string:
+		*e = Variant(s)
+	default:
+		return fmt.Errorf("unsupported scan type for Variant: %T", src)
+	}
+	return nil
+}
+
+type NullVariant struct {
+	Variant Variant
+	Valid   bool // Valid is true if Variant is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullVariant) Scan(value interface{}) error {
+	if value == nil {
+		ns.Variant, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.Variant.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullVariant) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.Variant), nil
+}
+
 type ChatMessage struct {
 	ID        uuid.UUID
 	RoomID    uuid.UUID
 	Message   string
-	Player    uuid.UUID
-	CreatedAt pgtype.Date
+	Player    pgtype.UUID
+	CreatedAt pgtype.Timestamp
+	Channel   ChatChannel
+	Team      int32
+}
+
+type ChatReport struct {
+	ID             uuid.UUID
+	MessageID      pgtype.UUID
+	RoomID         uuid.UUID
+	Reporter       uuid.UUID
+	ReportedPlayer uuid.UUID
+	Message        string
+	Reason         string
+	Reviewed       bool
+	CreatedAt      pgtype.Timestamp
+}
+
+type Deck struct {
+	ID          string
+	Shuffled    bool
+	Cards       []byte
+	CreatedAt   pgtype.Timestamp
+	Seed        int64
+	Composition []byte
 }
 
 type Game struct {
-	ID        uuid.UUID
-	CreatedAt pgtype.Timestamp
-	Result    []byte
-	State     State
-	Round     int32
-	DeckID    string
+	ID          uuid.UUID
+	CreatedAt   pgtype.Timestamp
+	Result      []byte
+	State       State
+	Round       int32
+	DeckID      string
+	Seed        int64
+	Variant     Variant
+	Vira        []byte
+	TeamSize    int32
+	TurnSeconds int32
+	HostID      pgtype.UUID
+	Status      LobbyStatus
+	ArchivedAt  pgtype.Timestamp
 }
 
 type Player struct {
@@ -78,4 +244,28 @@ type Player struct {
 	Name   string
 	RoomID uuid.UUID
 	Ordem  int32
+	Team   int32
+	Ready  bool
+	LeftAt pgtype.Timestamp
+}
+
+type PlayerHand struct {
+	ID        uuid.UUID
+	RoomID    uuid.UUID
+	PlayerID  uuid.UUID
+	Round     int32
+	Cards     []byte
+	CreatedAt pgtype.Timestamp
+}
+
+type ShuffleCommitment struct {
+	ID          uuid.UUID
+	RoomID      uuid.UUID
+	Round       int32
+	Hash        string
+	ServerSeed  string
+	ClientSeeds []byte
+	Revealed    bool
+	CreatedAt   pgtype.Timestamp
+	Seed        int64
 }
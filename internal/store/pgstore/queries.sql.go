@@ -9,39 +9,175 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createMessage = `-- name: CreateMessage :one
-INSERT INTO chat_messages 
-("room_id", "message", "player" )
-VALUES 
-($1, $2, $3)
+const archiveGameRoom = `-- name: ArchiveGameRoom :exec
+UPDATE games
+SET
+"status"='finished',
+"archived_at"=now()
+WHERE id=$1 AND archived_at IS NULL
+`
+
+func (q *Queries) ArchiveGameRoom(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, archiveGameRoom, id)
+	return err
+}
+
+const clearGameHost = `-- name: ClearGameHost :exec
+UPDATE games
+SET
+"host_id"=NULL
+WHERE id=$1 AND host_id=$2
+`
+
+type ClearGameHostParams struct {
+	ID     uuid.UUID
+	HostID pgtype.UUID
+}
+
+func (q *Queries) ClearGameHost(ctx context.Context, arg ClearGameHostParams) error {
+	_, err := q.db.Exec(ctx, clearGameHost, arg.ID, arg.HostID)
+	return err
+}
+
+const createChatReport = `-- name: CreateChatReport :one
+INSERT INTO chat_reports
+("message_id", "room_id", "reporter", "reported_player", "message", "reason")
+VALUES
+($1, $2, $3, $4, $5, $6)
 RETURNING "id"
 `
 
-type CreateMessageParams struct {
-	RoomID  uuid.UUID
-	Message string
-	Player  uuid.UUID
+type CreateChatReportParams struct {
+	MessageID      pgtype.UUID
+	RoomID         uuid.UUID
+	Reporter       uuid.UUID
+	ReportedPlayer uuid.UUID
+	Message        string
+	Reason         string
 }
 
-func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (uuid.UUID, error) {
-	row := q.db.QueryRow(ctx, createMessage, arg.RoomID, arg.Message, arg.Player)
+func (q *Queries) CreateChatReport(ctx context.Context, arg CreateChatReportParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createChatReport,
+		arg.MessageID,
+		arg.RoomID,
+		arg.Reporter,
+		arg.ReportedPlayer,
+		arg.Message,
+		arg.Reason,
+	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
+const createDeck = `-- name: CreateDeck :exec
+INSERT INTO decks
+("id", "shuffled", "cards", "seed", "composition")
+VALUES
+($1, $2, $3, $4, $5)
+`
+
+type CreateDeckParams struct {
+	ID          string
+	Shuffled    bool
+	Cards       []byte
+	Seed        int64
+	Composition []byte
+}
+
+func (q *Queries) CreateDeck(ctx context.Context, arg CreateDeckParams) error {
+	_, err := q.db.Exec(ctx, createDeck,
+		arg.ID,
+		arg.Shuffled,
+		arg.Cards,
+		arg.Seed,
+		arg.Composition,
+	)
+	return err
+}
+
+const createMessage = `-- name: CreateMessage :one 
+WITH message AS (
+    INSERT INTO chat_messages 
+    ("room_id", "message", "player", "channel", "team")
+    VALUES 
+    ($1, $2, $3, $4, $5)
+    RETURNING id, room_id, message, player, created_at, channel, team
+)
+SELECT 
+    message.id, message.room_id, message.message, message.player, message.created_at, message.channel, message.team, players.name AS player_name
+FROM message
+JOIN players ON players.id = message.player
+`
+
+type CreateMessageParams struct {
+	RoomID  uuid.UUID
+	Message string
+	Player  pgtype.UUID
+	Channel ChatChannel
+	Team    int32
+}
+
+type CreateMessageRow struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Message    string
+	Player     pgtype.UUID
+	CreatedAt  pgtype.Timestamp
+	Channel    ChatChannel
+	Team       int32
+	PlayerName string
+}
+
+func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (CreateMessageRow, error) {
+	row := q.db.QueryRow(ctx, createMessage,
+		arg.RoomID,
+		arg.Message,
+		arg.Player,
+		arg.Channel,
+		arg.Team,
+	)
+	var i CreateMessageRow
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.Message,
+		&i.Player,
+		&i.CreatedAt,
+		&i.Channel,
+		&i.Team,
+		&i.PlayerName,
+	)
+	return i, err
+}
+
 const createNewGame = `-- name: CreateNewGame :one
 INSERT INTO games 
-("state", "round", "created_at", "result", "deck_id")
+("state", "round", "created_at", "result", "deck_id", "seed", "variant", "team_size", "turn_seconds")
 VALUES 
-(DEFAULT, DEFAULT, DEFAULT, DEFAULT, $1)
-RETURNING id, created_at, result, state, round, deck_id
+(DEFAULT, DEFAULT, DEFAULT, DEFAULT, $1, $2, $3, $4, $5)
+RETURNING id, created_at, result, state, round, deck_id, seed, variant, vira, team_size, turn_seconds, host_id, status, archived_at
 `
 
-func (q *Queries) CreateNewGame(ctx context.Context, deckID string) (Game, error) {
-	row := q.db.QueryRow(ctx, createNewGame, deckID)
+type CreateNewGameParams struct {
+	DeckID      string
+	Seed        int64
+	Variant     Variant
+	TeamSize    int32
+	TurnSeconds int32
+}
+
+func (q *Queries) CreateNewGame(ctx context.Context, arg CreateNewGameParams) (Game, error) {
+	row := q.db.QueryRow(ctx, createNewGame,
+		arg.DeckID,
+		arg.Seed,
+		arg.Variant,
+		arg.TeamSize,
+		arg.TurnSeconds,
+	)
 	var i Game
 	err := row.Scan(
 		&i.ID,
@@ -50,6 +186,14 @@ func (q *Queries) CreateNewGame(ctx context.Context, deckID string) (Game, error
 		&i.State,
 		&i.Round,
 		&i.DeckID,
+		&i.Seed,
+		&i.Variant,
+		&i.Vira,
+		&i.TeamSize,
+		&i.TurnSeconds,
+		&i.HostID,
+		&i.Status,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
@@ -74,6 +218,71 @@ func (q *Queries) CreatePlayer(ctx context.Context, arg CreatePlayerParams) (uui
 	return id, err
 }
 
+const createPlayerHand = `-- name: CreatePlayerHand :exec
+INSERT INTO player_hands
+("room_id", "player_id", "round", "cards")
+VALUES
+($1, $2, $3, $4)
+ON CONFLICT (player_id, round) DO UPDATE
+SET "room_id"=EXCLUDED.room_id, "cards"=EXCLUDED.cards
+`
+
+type CreatePlayerHandParams struct {
+	RoomID   uuid.UUID
+	PlayerID uuid.UUID
+	Round    int32
+	Cards    []byte
+}
+
+func (q *Queries) CreatePlayerHand(ctx context.Context, arg CreatePlayerHandParams) error {
+	_, err := q.db.Exec(ctx, createPlayerHand,
+		arg.RoomID,
+		arg.PlayerID,
+		arg.Round,
+		arg.Cards,
+	)
+	return err
+}
+
+const createShuffleCommitment = `-- name: CreateShuffleCommitment :one
+INSERT INTO shuffle_commitments
+("room_id", "round", "hash", "server_seed", "client_seeds")
+VALUES
+($1, $2, $3, $4, $5)
+RETURNING id, room_id, round, hash, server_seed, client_seeds, revealed, created_at, seed
+`
+
+type CreateShuffleCommitmentParams struct {
+	RoomID      uuid.UUID
+	Round       int32
+	Hash        string
+	ServerSeed  string
+	ClientSeeds []byte
+}
+
+func (q *Queries) CreateShuffleCommitment(ctx context.Context, arg CreateShuffleCommitmentParams) (ShuffleCommitment, error) {
+	row := q.db.QueryRow(ctx, createShuffleCommitment,
+		arg.RoomID,
+		arg.Round,
+		arg.Hash,
+		arg.ServerSeed,
+		arg.ClientSeeds,
+	)
+	var i ShuffleCommitment
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.Round,
+		&i.Hash,
+		&i.ServerSeed,
+		&i.ClientSeeds,
+		&i.Revealed,
+		&i.CreatedAt,
+		&i.Seed,
+	)
+	return i, err
+}
+
 const deleteGameRoom = `-- name: DeleteGameRoom :one
 DELETE FROM games 
 WHERE
@@ -87,8 +296,36 @@ func (q *Queries) DeleteGameRoom(ctx context.Context, id uuid.UUID) (uuid.UUID,
 	return id, err
 }
 
+const deleteShuffleCommitment = `-- name: DeleteShuffleCommitment :exec
+DELETE FROM shuffle_commitments
+WHERE id=$1
+`
+
+func (q *Queries) DeleteShuffleCommitment(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteShuffleCommitment, id)
+	return err
+}
+
+const detachPlayer = `-- name: DetachPlayer :one
+UPDATE players
+SET
+"ordem"=-1,
+"ready"=false,
+"left_at"=now()
+WHERE id=$1
+RETURNING "room_id"
+`
+
+func (q *Queries) DetachPlayer(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, detachPlayer, id)
+	var room_id uuid.UUID
+	err := row.Scan(&room_id)
+	return room_id, err
+}
+
 const getAllRooms = `-- name: GetAllRooms :many
-SELECT id, created_at, result, state, round, deck_id FROM games
+SELECT id, created_at, result, state, round, deck_id, seed, variant, vira, team_size, turn_seconds, host_id, status, archived_at FROM games
+WHERE archived_at IS NULL
 `
 
 func (q *Queries) GetAllRooms(ctx context.Context) ([]Game, error) {
@@ -107,6 +344,14 @@ func (q *Queries) GetAllRooms(ctx context.Context) ([]Game, error) {
 			&i.State,
 			&i.Round,
 			&i.DeckID,
+			&i.Seed,
+			&i.Variant,
+			&i.Vira,
+			&i.TeamSize,
+			&i.TurnSeconds,
+			&i.HostID,
+			&i.Status,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -118,8 +363,27 @@ func (q *Queries) GetAllRooms(ctx context.Context) ([]Game, error) {
 	return items, nil
 }
 
+const getDeck = `-- name: GetDeck :one
+SELECT id, shuffled, cards, created_at, seed, composition FROM decks
+WHERE id=$1
+`
+
+func (q *Queries) GetDeck(ctx context.Context, id string) (Deck, error) {
+	row := q.db.QueryRow(ctx, getDeck, id)
+	var i Deck
+	err := row.Scan(
+		&i.ID,
+		&i.Shuffled,
+		&i.Cards,
+		&i.CreatedAt,
+		&i.Seed,
+		&i.Composition,
+	)
+	return i, err
+}
+
 const getGames = `-- name: GetGames :many
-SELECT id, created_at, result, state, round, deck_id FROM games
+SELECT id, created_at, result, state, round, deck_id, seed, variant, vira, team_size, turn_seconds, host_id, status, archived_at FROM games
 `
 
 func (q *Queries) GetGames(ctx context.Context) ([]Game, error) {
@@ -138,6 +402,14 @@ func (q *Queries) GetGames(ctx context.Context) ([]Game, error) {
 			&i.State,
 			&i.Round,
 			&i.DeckID,
+			&i.Seed,
+			&i.Variant,
+			&i.Vira,
+			&i.TeamSize,
+			&i.TurnSeconds,
+			&i.HostID,
+			&i.Status,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -150,7 +422,7 @@ func (q *Queries) GetGames(ctx context.Context) ([]Game, error) {
 }
 
 const getMessage = `-- name: GetMessage :one
-SELECT id, room_id, message, player, created_at FROM chat_messages
+SELECT id, room_id, message, player, created_at, channel, team FROM chat_messages
 WHERE id=$1
 `
 
@@ -163,12 +435,161 @@ func (q *Queries) GetMessage(ctx context.Context, id uuid.UUID) (ChatMessage, er
 		&i.Message,
 		&i.Player,
 		&i.CreatedAt,
+		&i.Channel,
+		&i.Team,
 	)
 	return i, err
 }
 
+const getPendingCommitment = `-- name: GetPendingCommitment :one
+SELECT id, room_id, round, hash, server_seed, client_seeds, revealed, created_at, seed FROM shuffle_commitments
+WHERE room_id=$1 AND round=$2 AND revealed=false
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetPendingCommitmentParams struct {
+	RoomID uuid.UUID
+	Round  int32
+}
+
+func (q *Queries) GetPendingCommitment(ctx context.Context, arg GetPendingCommitmentParams) (ShuffleCommitment, error) {
+	row := q.db.QueryRow(ctx, getPendingCommitment, arg.RoomID, arg.Round)
+	var i ShuffleCommitment
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.Round,
+		&i.Hash,
+		&i.ServerSeed,
+		&i.ClientSeeds,
+		&i.Revealed,
+		&i.CreatedAt,
+		&i.Seed,
+	)
+	return i, err
+}
+
+const getPlayerHand = `-- name: GetPlayerHand :one
+SELECT id, room_id, player_id, round, cards, created_at FROM player_hands
+WHERE player_id=$1 AND round=$2
+`
+
+type GetPlayerHandParams struct {
+	PlayerID uuid.UUID
+	Round    int32
+}
+
+func (q *Queries) GetPlayerHand(ctx context.Context, arg GetPlayerHandParams) (PlayerHand, error) {
+	row := q.db.QueryRow(ctx, getPlayerHand, arg.PlayerID, arg.Round)
+	var i PlayerHand
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.PlayerID,
+		&i.Round,
+		&i.Cards,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRecentRoomMessages = `-- name: GetRecentRoomMessages :many
+SELECT 
+    chat_messages.id, chat_messages.room_id, chat_messages.message, chat_messages.player, chat_messages.created_at, chat_messages.channel, chat_messages.team, COALESCE(players.name, '') AS player_name
+FROM chat_messages
+LEFT JOIN players ON players.id = chat_messages.player
+WHERE
+    chat_messages.room_id=$1
+    AND (chat_messages.channel='room' OR chat_messages.team=$2)
+ORDER BY chat_messages.created_at DESC, chat_messages.id DESC
+LIMIT $3
+`
+
+type GetRecentRoomMessagesParams struct {
+	RoomID uuid.UUID
+	Team   int32
+	Limit  int32
+}
+
+type GetRecentRoomMessagesRow struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Message    string
+	Player     pgtype.UUID
+	CreatedAt  pgtype.Timestamp
+	Channel    ChatChannel
+	Team       int32
+	PlayerName string
+}
+
+func (q *Queries) GetRecentRoomMessages(ctx context.Context, arg GetRecentRoomMessagesParams) ([]GetRecentRoomMessagesRow, error) {
+	rows, err := q.db.Query(ctx, getRecentRoomMessages, arg.RoomID, arg.Team, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentRoomMessagesRow
+	for rows.Next() {
+		var i GetRecentRoomMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.Message,
+			&i.Player,
+			&i.CreatedAt,
+			&i.Channel,
+			&i.Team,
+			&i.PlayerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRevealedCommitments = `-- name: GetRevealedCommitments :many
+SELECT id, room_id, round, hash, server_seed, client_seeds, revealed, created_at, seed FROM shuffle_commitments
+WHERE room_id=$1 AND revealed=true
+ORDER BY created_at
+`
+
+func (q *Queries) GetRevealedCommitments(ctx context.Context, roomID uuid.UUID) ([]ShuffleCommitment, error) {
+	rows, err := q.db.Query(ctx, getRevealedCommitments, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShuffleCommitment
+	for rows.Next() {
+		var i ShuffleCommitment
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.Round,
+			&i.Hash,
+			&i.ServerSeed,
+			&i.ClientSeeds,
+			&i.Revealed,
+			&i.CreatedAt,
+			&i.Seed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRoom = `-- name: GetRoom :one
-SELECT id, created_at, result, state, round, deck_id FROM games
+SELECT id, created_at, result, state, round, deck_id, seed, variant, vira, team_size, turn_seconds, host_id, status, archived_at FROM games
 WHERE id=$1
 `
 
@@ -182,30 +603,168 @@ func (q *Queries) GetRoom(ctx context.Context, id uuid.UUID) (Game, error) {
 		&i.State,
 		&i.Round,
 		&i.DeckID,
+		&i.Seed,
+		&i.Variant,
+		&i.Vira,
+		&i.TeamSize,
+		&i.TurnSeconds,
+		&i.HostID,
+		&i.Status,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
-const getRoomMessages = `-- name: GetRoomMessages :many
-SELECT id, room_id, message, player, created_at FROM chat_messages
-WHERE room_id=$1
+const getRoomCommitment = `-- name: GetRoomCommitment :one
+SELECT id, room_id, round, hash, server_seed, client_seeds, revealed, created_at, seed FROM shuffle_commitments
+WHERE room_id=$1 AND round=$2
 `
 
-func (q *Queries) GetRoomMessages(ctx context.Context, roomID uuid.UUID) ([]ChatMessage, error) {
-	rows, err := q.db.Query(ctx, getRoomMessages, roomID)
+type GetRoomCommitmentParams struct {
+	RoomID uuid.UUID
+	Round  int32
+}
+
+func (q *Queries) GetRoomCommitment(ctx context.Context, arg GetRoomCommitmentParams) (ShuffleCommitment, error) {
+	row := q.db.QueryRow(ctx, getRoomCommitment, arg.RoomID, arg.Round)
+	var i ShuffleCommitment
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.Round,
+		&i.Hash,
+		&i.ServerSeed,
+		&i.ClientSeeds,
+		&i.Revealed,
+		&i.CreatedAt,
+		&i.Seed,
+	)
+	return i, err
+}
+
+const getRoomMessagesAfter = `-- name: GetRoomMessagesAfter :many
+SELECT 
+    chat_messages.id, chat_messages.room_id, chat_messages.message, chat_messages.player, chat_messages.created_at, chat_messages.channel, chat_messages.team, COALESCE(players.name, '') AS player_name
+FROM chat_messages
+LEFT JOIN players ON players.id = chat_messages.player
+WHERE
+    chat_messages.room_id=$1
+    AND (chat_messages.channel='room' OR chat_messages.team=$2)
+    AND (chat_messages.created_at, chat_messages.id) > ($3::timestamp, $4::uuid)
+ORDER BY chat_messages.created_at, chat_messages.id
+LIMIT $5
+`
+
+type GetRoomMessagesAfterParams struct {
+	RoomID    uuid.UUID
+	Team      int32
+	CreatedAt pgtype.Timestamp
+	ID        uuid.UUID
+	Limit     int32
+}
+
+type GetRoomMessagesAfterRow struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Message    string
+	Player     pgtype.UUID
+	CreatedAt  pgtype.Timestamp
+	Channel    ChatChannel
+	Team       int32
+	PlayerName string
+}
+
+func (q *Queries) GetRoomMessagesAfter(ctx context.Context, arg GetRoomMessagesAfterParams) ([]GetRoomMessagesAfterRow, error) {
+	rows, err := q.db.Query(ctx, getRoomMessagesAfter,
+		arg.RoomID,
+		arg.Team,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ChatMessage
+	var items []GetRoomMessagesAfterRow
 	for rows.Next() {
-		var i ChatMessage
+		var i GetRoomMessagesAfterRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.RoomID,
 			&i.Message,
 			&i.Player,
 			&i.CreatedAt,
+			&i.Channel,
+			&i.Team,
+			&i.PlayerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomMessagesBefore = `-- name: GetRoomMessagesBefore :many
+SELECT 
+    chat_messages.id, chat_messages.room_id, chat_messages.message, chat_messages.player, chat_messages.created_at, chat_messages.channel, chat_messages.team, COALESCE(players.name, '') AS player_name
+FROM chat_messages
+LEFT JOIN players ON players.id = chat_messages.player
+WHERE
+    chat_messages.room_id=$1
+    AND (chat_messages.channel='room' OR chat_messages.team=$2)
+    AND (chat_messages.created_at, chat_messages.id) < ($3::timestamp, $4::uuid)
+ORDER BY chat_messages.created_at DESC, chat_messages.id DESC
+LIMIT $5
+`
+
+type GetRoomMessagesBeforeParams struct {
+	RoomID    uuid.UUID
+	Team      int32
+	CreatedAt pgtype.Timestamp
+	ID        uuid.UUID
+	Limit     int32
+}
+
+type GetRoomMessagesBeforeRow struct {
+	ID         uuid.UUID
+	RoomID     uuid.UUID
+	Message    string
+	Player     pgtype.UUID
+	CreatedAt  pgtype.Timestamp
+	Channel    ChatChannel
+	Team       int32
+	PlayerName string
+}
+
+func (q *Queries) GetRoomMessagesBefore(ctx context.Context, arg GetRoomMessagesBeforeParams) ([]GetRoomMessagesBeforeRow, error) {
+	rows, err := q.db.Query(ctx, getRoomMessagesBefore,
+		arg.RoomID,
+		arg.Team,
+		arg.CreatedAt,
+		arg.ID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoomMessagesBeforeRow
+	for rows.Next() {
+		var i GetRoomMessagesBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.Message,
+			&i.Player,
+			&i.CreatedAt,
+			&i.Channel,
+			&i.Team,
+			&i.PlayerName,
 		); err != nil {
 			return nil, err
 		}
@@ -222,7 +781,7 @@ SELECT
     "id" 
 FROM players 
 WHERE
-    room_id=$1
+    room_id=$1 AND left_at IS NULL
 ORDER BY ordem
 `
 
@@ -246,16 +805,173 @@ func (q *Queries) GetRoomPlayers(ctx context.Context, roomID uuid.UUID) ([]uuid.
 	return items, nil
 }
 
+const getRoomSeats = `-- name: GetRoomSeats :many
+SELECT
+    "id", "name", "ordem", "team", "ready"
+FROM players
+WHERE
+    room_id=$1 AND ordem >= 0
+ORDER BY ordem
+`
+
+type GetRoomSeatsRow struct {
+	ID    uuid.UUID
+	Name  string
+	Ordem int32
+	Team  int32
+	Ready bool
+}
+
+func (q *Queries) GetRoomSeats(ctx context.Context, roomID uuid.UUID) ([]GetRoomSeatsRow, error) {
+	rows, err := q.db.Query(ctx, getRoomSeats, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoomSeatsRow
+	for rows.Next() {
+		var i GetRoomSeatsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Ordem,
+			&i.Team,
+			&i.Ready,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const removePlayerFromRoom = `-- name: RemovePlayerFromRoom :one
 DELETE FROM players 
 WHERE id=$1
-RETURNING "id"
+RETURNING "room_id"
 `
 
 func (q *Queries) RemovePlayerFromRoom(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
 	row := q.db.QueryRow(ctx, removePlayerFromRoom, id)
-	err := row.Scan(&id)
-	return id, err
+	var room_id uuid.UUID
+	err := row.Scan(&room_id)
+	return room_id, err
+}
+
+const revealRoomCommitments = `-- name: RevealRoomCommitments :many
+UPDATE shuffle_commitments
+SET "revealed"=true
+WHERE room_id=$1 AND revealed=false AND round <= $2
+RETURNING id, room_id, round, hash, server_seed, client_seeds, revealed, created_at, seed
+`
+
+type RevealRoomCommitmentsParams struct {
+	RoomID uuid.UUID
+	Round  int32
+}
+
+func (q *Queries) RevealRoomCommitments(ctx context.Context, arg RevealRoomCommitmentsParams) ([]ShuffleCommitment, error) {
+	rows, err := q.db.Query(ctx, revealRoomCommitments, arg.RoomID, arg.Round)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShuffleCommitment
+	for rows.Next() {
+		var i ShuffleCommitment
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.Round,
+			&i.Hash,
+			&i.ServerSeed,
+			&i.ClientSeeds,
+			&i.Revealed,
+			&i.CreatedAt,
+			&i.Seed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setCommitmentSeeds = `-- name: SetCommitmentSeeds :exec
+UPDATE shuffle_commitments
+SET
+"client_seeds"=$2,
+"seed"=$3
+WHERE id=$1
+`
+
+type SetCommitmentSeedsParams struct {
+	ID          uuid.UUID
+	ClientSeeds []byte
+	Seed        int64
+}
+
+func (q *Queries) SetCommitmentSeeds(ctx context.Context, arg SetCommitmentSeedsParams) error {
+	_, err := q.db.Exec(ctx, setCommitmentSeeds, arg.ID, arg.ClientSeeds, arg.Seed)
+	return err
+}
+
+const setGameHost = `-- name: SetGameHost :exec
+UPDATE games
+SET
+"host_id"=$2
+WHERE id=$1 AND host_id IS NULL
+`
+
+type SetGameHostParams struct {
+	ID     uuid.UUID
+	HostID pgtype.UUID
+}
+
+func (q *Queries) SetGameHost(ctx context.Context, arg SetGameHostParams) error {
+	_, err := q.db.Exec(ctx, setGameHost, arg.ID, arg.HostID)
+	return err
+}
+
+const setGameScore = `-- name: SetGameScore :exec
+UPDATE games
+SET
+"round"=$1,
+"result"=$2
+WHERE id=$3
+`
+
+type SetGameScoreParams struct {
+	Round  int32
+	Result []byte
+	ID     uuid.UUID
+}
+
+func (q *Queries) SetGameScore(ctx context.Context, arg SetGameScoreParams) error {
+	_, err := q.db.Exec(ctx, setGameScore, arg.Round, arg.Result, arg.ID)
+	return err
+}
+
+const setGameVira = `-- name: SetGameVira :exec
+UPDATE games
+SET "vira"=$1
+WHERE id=$2
+`
+
+type SetGameViraParams struct {
+	Vira []byte
+	ID   uuid.UUID
+}
+
+func (q *Queries) SetGameVira(ctx context.Context, arg SetGameViraParams) error {
+	_, err := q.db.Exec(ctx, setGameVira, arg.Vira, arg.ID)
+	return err
 }
 
 const setOrder = `-- name: SetOrder :exec
@@ -274,6 +990,23 @@ func (q *Queries) SetOrder(ctx context.Context, arg SetOrderParams) error {
 	return err
 }
 
+const setPlayerReady = `-- name: SetPlayerReady :exec
+UPDATE players
+SET
+"ready"=$1
+WHERE id=$2
+`
+
+type SetPlayerReadyParams struct {
+	Ready bool
+	ID    uuid.UUID
+}
+
+func (q *Queries) SetPlayerReady(ctx context.Context, arg SetPlayerReadyParams) error {
+	_, err := q.db.Exec(ctx, setPlayerReady, arg.Ready, arg.ID)
+	return err
+}
+
 const setRoomState = `-- name: SetRoomState :exec
 UPDATE games 
 SET 
@@ -290,3 +1023,86 @@ func (q *Queries) SetRoomState(ctx context.Context, arg SetRoomStateParams) erro
 	_, err := q.db.Exec(ctx, setRoomState, arg.State, arg.ID)
 	return err
 }
+
+const setSeat = `-- name: SetSeat :exec
+UPDATE players
+SET
+"ordem"=$1,
+"team"=$2
+WHERE id=$3
+`
+
+type SetSeatParams struct {
+	Ordem int32
+	Team  int32
+	ID    uuid.UUID
+}
+
+func (q *Queries) SetSeat(ctx context.Context, arg SetSeatParams) error {
+	_, err := q.db.Exec(ctx, setSeat, arg.Ordem, arg.Team, arg.ID)
+	return err
+}
+
+const transitionGameStatus = `-- name: TransitionGameStatus :execrows
+UPDATE games
+SET
+"status"=$1
+WHERE id=$2 AND "status"=$3
+`
+
+type TransitionGameStatusParams struct {
+	Next    LobbyStatus
+	ID      uuid.UUID
+	Current LobbyStatus
+}
+
+func (q *Queries) TransitionGameStatus(ctx context.Context, arg TransitionGameStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transitionGameStatus, arg.Next, arg.ID, arg.Current)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateDeck = `-- name: UpdateDeck :exec
+UPDATE decks
+SET
+"shuffled"=$1,
+"cards"=$2,
+"seed"=$3
+WHERE id=$4
+`
+
+type UpdateDeckParams struct {
+	Shuffled bool
+	Cards    []byte
+	Seed     int64
+	ID       string
+}
+
+func (q *Queries) UpdateDeck(ctx context.Context, arg UpdateDeckParams) error {
+	_, err := q.db.Exec(ctx, updateDeck,
+		arg.Shuffled,
+		arg.Cards,
+		arg.Seed,
+		arg.ID,
+	)
+	return err
+}
+
+const updatePlayerHand = `-- name: UpdatePlayerHand :exec
+UPDATE player_hands
+SET "cards"=$1
+WHERE player_id=$2 AND round=$3
+`
+
+type UpdatePlayerHandParams struct {
+	Cards    []byte
+	PlayerID uuid.UUID
+	Round    int32
+}
+
+func (q *Queries) UpdatePlayerHand(ctx context.Context, arg UpdatePlayerHandParams) error {
+	_, err := q.db.Exec(ctx, updatePlayerHand, arg.Cards, arg.PlayerID, arg.Round)
+	return err
+}
@@ -3,9 +3,9 @@ SELECT * FROM games;
 
 -- name: CreateNewGame :one
 INSERT INTO games 
-("state", "round", "created_at", "result", "deck_id")
+("state", "round", "created_at", "result", "deck_id", "seed", "variant", "team_size", "turn_seconds")
 VALUES 
-(DEFAULT, DEFAULT, DEFAULT, DEFAULT, $1)
+(DEFAULT, DEFAULT, DEFAULT, DEFAULT, $1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetRoom :one
@@ -24,25 +24,62 @@ SELECT
     "id" 
 FROM players 
 WHERE
-    room_id=$1
+    room_id=$1 AND left_at IS NULL
 ORDER BY ordem;
 
 
 
 -- name: CreateMessage :one 
-INSERT INTO chat_messages 
-("room_id", "message", "player" )
-VALUES 
-($1, $2, $3)
-RETURNING "id";
+WITH message AS (
+    INSERT INTO chat_messages 
+    ("room_id", "message", "player", "channel", "team")
+    VALUES 
+    ($1, $2, $3, $4, $5)
+    RETURNING *
+)
+SELECT 
+    message.*, players.name AS player_name
+FROM message
+JOIN players ON players.id = message.player;
 
 -- name: GetMessage :one
 SELECT * FROM chat_messages
 WHERE id=$1;
 
--- name: GetRoomMessages :many
-SELECT * FROM chat_messages
-WHERE room_id=$1;
+-- name: GetRecentRoomMessages :many
+SELECT 
+    chat_messages.*, COALESCE(players.name, '') AS player_name
+FROM chat_messages
+LEFT JOIN players ON players.id = chat_messages.player
+WHERE
+    chat_messages.room_id=$1
+    AND (chat_messages.channel='room' OR chat_messages.team=$2)
+ORDER BY chat_messages.created_at DESC, chat_messages.id DESC
+LIMIT $3;
+
+-- name: GetRoomMessagesBefore :many
+SELECT 
+    chat_messages.*, COALESCE(players.name, '') AS player_name
+FROM chat_messages
+LEFT JOIN players ON players.id = chat_messages.player
+WHERE
+    chat_messages.room_id=sqlc.arg(room_id)
+    AND (chat_messages.channel='room' OR chat_messages.team=sqlc.arg(team))
+    AND (chat_messages.created_at, chat_messages.id) < (sqlc.arg(created_at)::timestamp, sqlc.arg(id)::uuid)
+ORDER BY chat_messages.created_at DESC, chat_messages.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetRoomMessagesAfter :many
+SELECT 
+    chat_messages.*, COALESCE(players.name, '') AS player_name
+FROM chat_messages
+LEFT JOIN players ON players.id = chat_messages.player
+WHERE
+    chat_messages.room_id=sqlc.arg(room_id)
+    AND (chat_messages.channel='room' OR chat_messages.team=sqlc.arg(team))
+    AND (chat_messages.created_at, chat_messages.id) > (sqlc.arg(created_at)::timestamp, sqlc.arg(id)::uuid)
+ORDER BY chat_messages.created_at, chat_messages.id
+LIMIT sqlc.arg('limit');
 
 -- name: SetRoomState :exec
 UPDATE games 
@@ -60,13 +97,164 @@ RETURNING "id";
 -- name: RemovePlayerFromRoom :one
 DELETE FROM players 
 WHERE id=$1
-RETURNING "id";
+RETURNING "room_id";
+
+-- name: DetachPlayer :one
+UPDATE players
+SET
+"ordem"=-1,
+"ready"=false,
+"left_at"=now()
+WHERE id=$1
+RETURNING "room_id";
+
+-- name: ClearGameHost :exec
+UPDATE games
+SET
+"host_id"=NULL
+WHERE id=$1 AND host_id=$2;
 
 -- name: GetAllRooms :many
-SELECT * FROM games;
+SELECT * FROM games
+WHERE archived_at IS NULL;
 
 
 -- name: SetOrder :exec
 UPDATE players 
 SET "ordem"=$1
-WHERE id=$2;
\ No newline at end of file
+WHERE id=$2;
+
+-- name: CreateDeck :exec
+INSERT INTO decks
+("id", "shuffled", "cards", "seed", "composition")
+VALUES
+($1, $2, $3, $4, $5);
+
+-- name: GetDeck :one
+SELECT * FROM decks
+WHERE id=$1;
+
+-- name: UpdateDeck :exec
+UPDATE decks
+SET
+"shuffled"=$1,
+"cards"=$2,
+"seed"=$3
+WHERE id=$4;
+
+
+-- name: CreateShuffleCommitment :one
+INSERT INTO shuffle_commitments
+("room_id", "round", "hash", "server_seed", "client_seeds")
+VALUES
+($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: RevealRoomCommitments :many
+UPDATE shuffle_commitments
+SET "revealed"=true
+WHERE room_id=$1 AND revealed=false AND round <= $2
+RETURNING *;
+
+-- name: GetRevealedCommitments :many
+SELECT * FROM shuffle_commitments
+WHERE room_id=$1 AND revealed=true
+ORDER BY created_at;
+
+-- name: GetPendingCommitment :one
+SELECT * FROM shuffle_commitments
+WHERE room_id=$1 AND round=$2 AND revealed=false
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetRoomCommitment :one
+SELECT * FROM shuffle_commitments
+WHERE room_id=$1 AND round=$2;
+
+-- name: SetCommitmentSeeds :exec
+UPDATE shuffle_commitments
+SET
+"client_seeds"=$2,
+"seed"=$3
+WHERE id=$1;
+
+-- name: DeleteShuffleCommitment :exec
+DELETE FROM shuffle_commitments
+WHERE id=$1;
+
+
+-- name: CreatePlayerHand :exec
+INSERT INTO player_hands
+("room_id", "player_id", "round", "cards")
+VALUES
+($1, $2, $3, $4)
+ON CONFLICT (player_id, round) DO UPDATE
+SET "room_id"=EXCLUDED.room_id, "cards"=EXCLUDED.cards;
+
+-- name: GetPlayerHand :one
+SELECT * FROM player_hands
+WHERE player_id=$1 AND round=$2;
+
+-- name: SetGameVira :exec
+UPDATE games
+SET "vira"=$1
+WHERE id=$2;
+
+-- name: UpdatePlayerHand :exec
+UPDATE player_hands
+SET "cards"=$1
+WHERE player_id=$2 AND round=$3;
+
+-- name: SetGameScore :exec
+UPDATE games
+SET
+"round"=$1,
+"result"=$2
+WHERE id=$3;
+
+-- name: GetRoomSeats :many
+SELECT
+    "id", "name", "ordem", "team", "ready"
+FROM players
+WHERE
+    room_id=$1 AND ordem >= 0
+ORDER BY ordem;
+
+-- name: SetSeat :exec
+UPDATE players
+SET
+"ordem"=$1,
+"team"=$2
+WHERE id=$3;
+
+-- name: SetGameHost :exec
+UPDATE games
+SET
+"host_id"=$2
+WHERE id=$1 AND host_id IS NULL;
+
+-- name: CreateChatReport :one
+INSERT INTO chat_reports
+("message_id", "room_id", "reporter", "reported_player", "message", "reason")
+VALUES
+($1, $2, $3, $4, $5, $6)
+RETURNING "id";
+
+-- name: TransitionGameStatus :execrows
+UPDATE games
+SET
+"status"=sqlc.arg(next)
+WHERE id=sqlc.arg(id) AND "status"=sqlc.arg(current);
+
+-- name: ArchiveGameRoom :exec
+UPDATE games
+SET
+"status"='finished',
+"archived_at"=now()
+WHERE id=$1 AND archived_at IS NULL;
+
+-- name: SetPlayerReady :exec
+UPDATE players
+SET
+"ready"=$1
+WHERE id=$2;